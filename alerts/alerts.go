@@ -0,0 +1,267 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Alerts about failures helpers have to act on, posted to the bot troubleshooting channel
+
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/metrics"
+
+	"github.com/amatsagu/tempest"
+)
+
+// The kinds of failures alerts are sent for
+const (
+	KIND_DATABASE    = "database"    // The database could not be read or written
+	KIND_PERMISSIONS = "permissions" // Discord refused a request, usually because the bot is missing a permission
+	KIND_GITHUB      = "github"      // GitHub did not create an issue
+	KIND_PANIC       = "panic"       // A handler panicked
+)
+
+// How long the same alert is not sent again for. Repeats are counted, and reported with the next one sent.
+const DEDUP_WINDOW = 15 * time.Minute
+
+// At most RATE_LIMIT alerts are sent every RATE_INTERVAL, so that an outage does not flood the channel.
+// The alerts dropped are counted, and reported with the next one sent.
+const RATE_LIMIT = 10
+const RATE_INTERVAL = 10 * time.Minute
+
+// Color of the alert embeds
+const alertColor = 0xed4245
+
+// Discord's limits on the size of embeds
+const (
+	maxTitleLength       = 256
+	maxDescriptionLength = 4096
+)
+
+// Alert describes a failure
+type Alert struct {
+	Kind        string               // One of the KIND_ constants
+	Summary     string               // What the bot failed to do
+	Fix         string               // What helpers can do about it, if anything
+	Err         error                // Why it failed, if known
+	Details     string               // Anything else worth showing, such as a stack trace
+	Interaction *tempest.Interaction // The interaction being handled when it failed, if any
+	Ticket      int64                // The ticket affected, if known
+}
+
+// Alerter posts alerts to a channel, dropping the ones that were sent recently and the ones over the rate limit
+type Alerter struct {
+	channelID tempest.Snowflake
+	now       func() time.Time
+
+	mu      sync.Mutex
+	recent  map[string]*recentAlert // The alerts sent in the last DEDUP_WINDOW, or repeated since, by key
+	sent    []time.Time             // When the alerts of the last RATE_INTERVAL were sent
+	dropped int                     // Alerts dropped by the rate limit since the last one sent
+}
+
+type recentAlert struct {
+	sentAt  time.Time
+	repeats int // Times the alert happened again since it was sent
+}
+
+// New returns an alerter posting to the channel
+func New(channelID tempest.Snowflake) *Alerter {
+	return &Alerter{
+		channelID: channelID,
+		now:       time.Now,
+		recent:    map[string]*recentAlert{},
+	}
+}
+
+// Report posts the alert, unless the same one was sent in the last DEDUP_WINDOW or the rate limit is reached.
+// Failing to post it is only logged, as there is nowhere else to report it.
+func (a *Alerter) Report(client *tempest.BaseClient, alert Alert) {
+	repeats, since, dropped, ok := a.admit(alert)
+	if !ok {
+		return
+	}
+
+	// Mentions in embeds never ping, so the user is not notified of the alert
+	_, err := client.SendMessage(a.channelID, tempest.Message{
+		Embeds: []tempest.Embed{a.embed(alert, repeats, since, dropped)},
+	}, nil)
+	if err != nil {
+		slog.Error("failed to send alert", "kind", alert.Kind, "summary", alert.Summary, "error", err)
+		metrics.Alerts.WithLabelValues(alert.Kind, metrics.ALERT_FAILED).Inc()
+		return
+	}
+
+	metrics.Alerts.WithLabelValues(alert.Kind, metrics.ALERT_SENT).Inc()
+}
+
+// Decide whether to send the alert. If so, returns how many times it was repeated since it was last sent (at
+// `since`), and how many alerts the rate limit dropped since the last one sent.
+func (a *Alerter) admit(alert Alert) (repeats int, since time.Time, dropped int, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	key := alertKey(alert)
+
+	previous := a.recent[key]
+	if previous != nil && now.Sub(previous.sentAt) < DEDUP_WINDOW {
+		previous.repeats++
+		metrics.Alerts.WithLabelValues(alert.Kind, metrics.ALERT_DUPLICATE).Inc()
+		return 0, time.Time{}, 0, false
+	}
+
+	for len(a.sent) > 0 && now.Sub(a.sent[0]) >= RATE_INTERVAL {
+		a.sent = a.sent[1:]
+	}
+	if len(a.sent) >= RATE_LIMIT {
+		a.dropped++
+		metrics.Alerts.WithLabelValues(alert.Kind, metrics.ALERT_RATE_LIMITED).Inc()
+		return 0, time.Time{}, 0, false
+	}
+
+	if previous != nil {
+		repeats, since = previous.repeats, previous.sentAt
+	}
+	dropped = a.dropped
+
+	a.sent = append(a.sent, now)
+	a.dropped = 0
+	a.recent[key] = &recentAlert{sentAt: now}
+
+	// Forget the alerts that can be sent again, unless their repeats are still to be reported
+	for k, r := range a.recent {
+		if now.Sub(r.sentAt) >= DEDUP_WINDOW && r.repeats == 0 {
+			delete(a.recent, k)
+		}
+	}
+
+	return repeats, since, dropped, true
+}
+
+// Alerts with the same key are duplicates of each other
+func alertKey(alert Alert) string {
+	var err string
+	if alert.Err != nil {
+		err = alert.Err.Error()
+	}
+
+	return alert.Kind + "\x00" + alert.Summary + "\x00" + err
+}
+
+// Build the embed describing the alert, and what happened since the last ones
+func (a *Alerter) embed(alert Alert, repeats int, since time.Time, dropped int) tempest.Embed {
+	var description strings.Builder
+	if alert.Fix != "" {
+		description.WriteString(alert.Fix + "\n")
+	}
+	if alert.Err != nil {
+		description.WriteString(codeBlock(alert.Err.Error()))
+	}
+	if alert.Details != "" {
+		description.WriteString(codeBlock(alert.Details))
+	}
+
+	fields := []tempest.EmbedField{{Name: "Kind", Value: alert.Kind, Inline: true}}
+	if alert.Ticket != 0 {
+		fields = append(fields, tempest.EmbedField{Name: "Ticket", Value: "#" + strconv.FormatInt(alert.Ticket, 10), Inline: true})
+	}
+	if itx := alert.Interaction; itx != nil {
+		if name := interactionName(itx); name != "" {
+			fields = append(fields, tempest.EmbedField{Name: "Interaction", Value: "`" + name + "`", Inline: true})
+		}
+		if user := interactionUser(itx); user != nil {
+			fields = append(fields, tempest.EmbedField{Name: "User", Value: fmt.Sprintf("%s (%s)", user.Mention(), user.Username), Inline: true})
+		}
+		if itx.ChannelID != 0 {
+			fields = append(fields, tempest.EmbedField{Name: "Channel", Value: fmt.Sprintf("<#%d>", itx.ChannelID), Inline: true})
+		}
+		fields = append(fields, tempest.EmbedField{Name: "Interaction ID", Value: itx.ID.String(), Inline: true})
+	}
+
+	var notes []string
+	if repeats > 0 {
+		notes = append(notes, fmt.Sprintf("Happened %d more times since <t:%d:R>.", repeats, since.Unix()))
+	}
+	if dropped > 0 {
+		notes = append(notes, fmt.Sprintf("%d other alerts were dropped to stay under the rate limit, see the logs.", dropped))
+	}
+	if len(notes) > 0 {
+		fields = append(fields, tempest.EmbedField{Name: "Also", Value: strings.Join(notes, "\n")})
+	}
+
+	now := a.now()
+	return tempest.Embed{
+		Title:       truncate(alert.Summary, maxTitleLength),
+		Description: truncate(description.String(), maxDescriptionLength),
+		Color:       alertColor,
+		Fields:      fields,
+		Timestamp:   &now,
+	}
+}
+
+// Wrap `s` in a code block, so that it is shown as is
+func codeBlock(s string) string {
+	return "```\n" + strings.ReplaceAll(strings.TrimSpace(s), "```", "'''") + "\n```\n"
+}
+
+// Cut `s` to at most `length` characters. Cutting a code block short leaves it unterminated, which Discord shows fine.
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length-1]) + "…"
+}
+
+// Return the command (with its subcommands) or custom ID of the interaction
+func interactionName(itx *tempest.Interaction) string {
+	type option struct {
+		Name    string             `json:"name"`
+		Type    tempest.OptionType `json:"type"`
+		Options []option           `json:"options"`
+	}
+	var data struct {
+		option
+		CustomID string `json:"custom_id"`
+	}
+	if json.Unmarshal(itx.Data, &data) != nil {
+		return ""
+	}
+
+	if data.CustomID != "" {
+		return data.CustomID
+	}
+	if data.Name == "" {
+		return ""
+	}
+
+	name := "/" + data.Name
+	options := data.Options
+	for len(options) > 0 && (options[0].Type == tempest.SUB_OPTION_TYPE || options[0].Type == tempest.SUB_COMMAND_GROUP_OPTION_TYPE) {
+		name += " " + options[0].Name
+		options = options[0].Options
+	}
+
+	return name
+}
+
+// Return the user who triggered the interaction
+func interactionUser(itx *tempest.Interaction) *tempest.User {
+	if itx.Member != nil && itx.Member.User != nil {
+		return itx.Member.User
+	}
+
+	return itx.User
+}
@@ -0,0 +1,144 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/responses"
+
+	"github.com/amatsagu/tempest"
+)
+
+// The role needed to use each command, unless the configuration says otherwise.
+// Every command the bot registers must be listed here, subcommands included, except the canned response commands,
+// which need the helper role. Any other command is refused to everyone but administrators (see authorize).
+// The RequiredPermissions of the commands only hide them in the Discord UI by default, and guild admins can change
+// that, so the bot checks these roles itself before running a command.
+var defaultCommandRoles = map[string]string{
+	"ping":                 config.ROLE_EVERYONE,
+	"new-issue":            config.ROLE_EVERYONE,
+	"send-ticket-message":  config.ROLE_ADMIN,
+	"get-user-ticket":      config.ROLE_HELPER,
+	"claim":                config.ROLE_HELPER,
+	"unclaim":              config.ROLE_HELPER,
+	"assign":               config.ROLE_SENIOR_HELPER,
+	"queue":                config.ROLE_HELPER,
+	"close":                config.ROLE_SENIOR_HELPER,
+	"transcript":           config.ROLE_HELPER,
+	"old-account default":  config.ROLE_HELPER,
+	"old-account specific": config.ROLE_HELPER,
+	"say":                  config.ROLE_HELPER,
+	"reply":                config.ROLE_HELPER,
+	"response create":      config.ROLE_SENIOR_HELPER,
+	"response edit":        config.ROLE_SENIOR_HELPER,
+	"response delete":      config.ROLE_SENIOR_HELPER,
+	"response list":        config.ROLE_HELPER,
+	"response preview":     config.ROLE_HELPER,
+	"ticket-ban add":       config.ROLE_SENIOR_HELPER,
+	"ticket-ban remove":    config.ROLE_SENIOR_HELPER,
+	"ticket-ban list":      config.ROLE_HELPER,
+}
+
+// Return the role needed to use the command, named like in the metrics ("group subcommand" for subcommands),
+// and false if the command has none
+func (b *Bot) commandRole(name string) (string, bool) {
+	// The configuration may set the role of a whole group
+	group, _, _ := strings.Cut(name, " ")
+	if role, ok := b.Config.CommandRoles[name]; ok {
+		return role, true
+	}
+	if role, ok := b.Config.CommandRoles[group]; ok {
+		return role, true
+	}
+
+	if role, ok := defaultCommandRoles[name]; ok {
+		return role, true
+	}
+	if _, ok := responses.Find(name); ok {
+		return config.ROLE_HELPER, true
+	}
+
+	return "", false
+}
+
+// Return whether the member has the role, or a more privileged one
+func (b *Bot) hasRole(member *tempest.Member, role string) bool {
+	if role == config.ROLE_EVERYONE {
+		return true
+	}
+	if member == nil {
+		return false
+	}
+
+	// Discord includes the permissions the member has in the channel, which always include Administrator for admins
+	if member.PermissionFlags&tempest.ADMINISTRATOR_PERMISSION_FLAG != 0 {
+		return true
+	}
+
+	seniorHelperRoleID := b.Config.Discord.SeniorHelperRoleID
+	if seniorHelperRoleID == 0 {
+		seniorHelperRoleID = b.Config.Discord.HelperRoleID
+	}
+
+	switch role {
+	case config.ROLE_HELPER:
+		return slices.Contains(member.RoleIDs, b.Config.Discord.HelperRoleID) || slices.Contains(member.RoleIDs, seniorHelperRoleID)
+	case config.ROLE_SENIOR_HELPER:
+		return slices.Contains(member.RoleIDs, seniorHelperRoleID)
+	default:
+		return false
+	}
+}
+
+// Return the role needed to use the command, and whether the member who used it has that role
+func (b *Bot) allowed(itx *tempest.Interaction, name string) (string, bool) {
+	role, ok := b.commandRole(name)
+	if !ok {
+		// Fail closed, so that a command added without a role is not open to everyone
+		logging.For(itx).Error("command is missing from defaultCommandRoles, only administrators may use it until it is added")
+		role = config.ROLE_ADMIN
+	}
+
+	return role, b.hasRole(itx.Member, role)
+}
+
+// Return whether the user of the command may use it, replying with why not if they may not
+func (b *Bot) authorize(itx *tempest.CommandInteraction, name string) bool {
+	role, ok := b.allowed(itx.Interaction, name)
+	if ok {
+		return true
+	}
+
+	logging.For(itx.Interaction).Info("refused command to member without the required role", "required_role", role)
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         fmt.Sprintf("You can't use `/%s`: %s.", name, b.roleDescription(role)),
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, true, nil)
+
+	return false
+}
+
+// Describe who has the role, to tell members why they can't use a command
+func (b *Bot) roleDescription(role string) string {
+	switch role {
+	case config.ROLE_HELPER:
+		return fmt.Sprintf("it is reserved to <@&%d>", b.Config.Discord.HelperRoleID)
+	case config.ROLE_SENIOR_HELPER:
+		if b.Config.Discord.SeniorHelperRoleID != 0 {
+			return fmt.Sprintf("it is reserved to <@&%d>", b.Config.Discord.SeniorHelperRoleID)
+		}
+		return fmt.Sprintf("it is reserved to <@&%d>", b.Config.Discord.HelperRoleID)
+	default:
+		return "it is reserved to server administrators"
+	}
+}
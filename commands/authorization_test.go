@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/discordtest"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Every command registered in main.go needs a role, or only administrators could use it
+func TestEveryCommandHasARole(t *testing.T) {
+	b := New(&config.Config{}, nil, nil)
+
+	names := []string{
+		PingCommand.Name,
+		b.CreateSupportTicketCommand().Name,
+		b.GetUserTicketCommand().Name,
+		b.ClaimCommand().Name,
+		b.UnclaimCommand().Name,
+		b.AssignCommand().Name,
+		b.QueueCommand().Name,
+		b.CloseCommand().Name,
+		b.TranscriptCommand().Name,
+		b.SayCommand().Name,
+		b.ReplyCommand().Name,
+		NewIssueCommand.Name,
+	}
+	for _, sub := range []string{b.OldAccountDefault().Name, b.OldAccountSpecific().Name} {
+		names = append(names, OldAccountCommandGroup.Name+" "+sub)
+	}
+	for _, sub := range []string{b.ResponseCreate().Name, b.ResponseEdit().Name, b.ResponseDelete().Name, b.ResponseList().Name, b.ResponsePreview().Name} {
+		names = append(names, ResponseCommandGroup.Name+" "+sub)
+	}
+	for _, sub := range []string{b.TicketBanAdd().Name, b.TicketBanRemove().Name, b.TicketBanList().Name} {
+		names = append(names, TicketBanCommandGroup.Name+" "+sub)
+	}
+
+	for _, name := range names {
+		if _, ok := b.commandRole(name); !ok {
+			t.Errorf("/%s is missing from defaultCommandRoles", name)
+		}
+	}
+}
+
+// A command missing from defaultCommandRoles is refused to helpers, but not to administrators
+func TestUnlistedCommandFailsClosed(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	var ran atomic.Bool
+	err := b.client.RegisterCommand(b.Command(tempest.Command{
+		Name: "unlisted",
+		SlashCommandHandler: func(itx *tempest.CommandInteraction) {
+			ran.Store(true)
+			itx.SendLinearReply("Done", true)
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := b.send(discordtest.CommandInteraction(helperOrigin(testHelper, testTicketChannelID), "unlisted"))
+	if ran.Load() || !strings.Contains(response.Data.Content, "reserved to server administrators") {
+		t.Errorf("a helper using an unlisted command was answered %q", response.Data.Content)
+	}
+
+	admin := helperOrigin(testHelper, testTicketChannelID)
+	admin.Member.PermissionFlags = tempest.ADMINISTRATOR_PERMISSION_FLAG
+	b.send(discordtest.CommandInteraction(admin, "unlisted"))
+	if !ran.Load() {
+		t.Error("an administrator could not use an unlisted command")
+	}
+}
+
+// Members who can't use /reply get no autocomplete choices, so they can't list the canned responses
+func TestReplyAutoCompleteNeedsRole(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.loadCannedResponses()
+
+	err := b.client.RegisterCommand(b.Command(b.ReplyCommand()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	choices := func(origin discordtest.Origin) []tempest.CommandOptionChoice {
+		w, err := b.signer.Send(b.client.DiscordRequestHandler,
+			discordtest.AutoCompleteInteraction(origin, "reply", "response", discordtest.Option("response", "gre")))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var response tempest.ResponseAutoComplete
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		if err != nil || response.Data == nil {
+			t.Fatalf("the autocomplete was answered %d %q: %v", w.Code, w.Body.String(), err)
+		}
+		return response.Data.Choices
+	}
+
+	if got := choices(helperOrigin(testHelper, testTicketChannelID)); len(got) != 1 || got[0].Value != "greet" {
+		t.Errorf("a helper got the choices %+v, want greet", got)
+	}
+	if got := choices(userOrigin()); len(got) != 0 {
+		t.Errorf("a member without the helper role got the choices %+v", got)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/types"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	githubClient "github.com/pagefaultgames/ticketune/github-client"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Bot holds what the command handlers depend on, so that they can be run against fakes.
+// Handlers reach Discord through the client that received the interaction (itx.Client).
+type Bot struct {
+	Config *config.Config       // The validated configuration
+	Store  db.Store             // Where tickets and canned responses are stored
+	GitHub *githubClient.Client // Used to open issues in Config.GitHub.Repo
+	Alerts *alerts.Alerter      // Posts failures to Config.Discord.TroubleshootingChannelID
+
+	cannedCommandsMu sync.Mutex                 // Held while the canned response commands are reloaded and synced
+	fileResponses    []responses.CannedResponse // The canned responses loaded from the file at startup
+	cannedCommands   map[string]bool            // Names of the canned response commands registered with tempest
+
+	pendingCannedResponsesMu sync.Mutex
+	pendingCannedResponses   map[tempest.Snowflake]pendingCannedResponse // Canned responses being edited in a modal, by helper
+
+	ticketLimiter ticketRateLimiter // Limits the tickets opened by everyone together, see Config.Cooldown
+
+	tasks          tasks       // The running interaction handlers and background jobs, see Shutdown
+	commandsSynced atomic.Bool // Whether the commands were sent to Discord, see CommandsSynced
+}
+
+// Create a bot using the given configuration, store and GitHub client
+func New(cfg *config.Config, store db.Store, gh *githubClient.Client) *Bot {
+	return &Bot{
+		Config:                 cfg,
+		Store:                  store,
+		GitHub:                 gh,
+		Alerts:                 alerts.New(cfg.Discord.TroubleshootingChannelID),
+		cannedCommands:         map[string]bool{},
+		pendingCannedResponses: map[tempest.Snowflake]pendingCannedResponse{},
+	}
+}
+
+// Return the channels the threads of each ticket category are created in
+func (b *Bot) ticketChannelIDs() []tempest.Snowflake {
+	categories := b.Config.TicketCategories()
+	ids := make([]tempest.Snowflake, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, category.ChannelID)
+	}
+
+	return ids
+}
+
+// Return whether the channel is a ticket thread
+func (b *Bot) isTicketChannel(channel types.Channel) bool {
+	return utils.CheckIfTicketChannel(channel, b.ticketChannelIDs())
+}
+
+// Return whether the member is a helper, senior helper or admin
+func (b *Bot) isHelper(member *tempest.Member) bool {
+	return b.hasRole(member, config.ROLE_HELPER)
+}
+
+// Get the user of the ticket in the command's thread, see utils.GetUserFromThread
+func (b *Bot) getUserFromThread(itx *tempest.CommandInteraction) (tempest.Snowflake, error) {
+	return utils.GetUserFromThread(itx, b.Store, b.ticketChannelIDs())
+}
+
+// Send a message to the ticket in the command's thread, see utils.SayCommandTemplate
+func (b *Bot) say(itx *tempest.CommandInteraction, content string, invokerResponse string, opts utils.SayOptions) {
+	utils.SayCommandTemplate(itx, b.Store, b.ticketChannelIDs(), content, invokerResponse, opts)
+}
+
+// Report a failure to the bot troubleshooting channel. It is sent in the background, so that the interaction being
+// handled (if any) is answered first.
+func (b *Bot) alert(client *tempest.BaseClient, alert alerts.Alert) {
+	b.Go(client, alertJob, func() {
+		b.Alerts.Report(client, alert)
+	})
+}
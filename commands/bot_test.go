@@ -0,0 +1,224 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/discordtest"
+	"github.com/pagefaultgames/ticketune/types"
+
+	"github.com/amatsagu/tempest"
+)
+
+const (
+	testApplicationID       tempest.Snowflake = 100
+	testGuildID             tempest.Snowflake = 101
+	testHelperRoleID        tempest.Snowflake = 102
+	testTicketChannelID     tempest.Snowflake = 103
+	testTroubleshootingID   tempest.Snowflake = 104
+	testTranscriptChannelID tempest.Snowflake = 105
+)
+
+// How long to wait for the requests handlers make after answering the interaction
+const testTimeout = 5 * time.Second
+
+var (
+	testUser   = tempest.User{ID: 200, Username: "player"}
+	testHelper = tempest.User{ID: 201, Username: "helper"}
+)
+
+// testBot is a Bot backed by a MemoryStore, answering interactions signed like Discord's through a tempest HTTP
+// client whose REST requests go to a fake Discord API
+type testBot struct {
+	*Bot
+	t      *testing.T
+	store  *db.MemoryStore
+	srv    *discordtest.Server
+	signer *discordtest.Signer
+	client tempest.HTTPClient
+}
+
+// Create a test bot with the ticket channels and users of the constants above.
+// `configure` (if not nil) changes the configuration before the bot is created.
+func newTestBot(t *testing.T, configure func(cfg *config.Config)) *testBot {
+	t.Helper()
+
+	srv := discordtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	signer, err := discordtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Discord.Token = "MTAw.test.token"
+	cfg.Discord.PublicKey = signer.PublicKey()
+	cfg.Discord.GuildID = testGuildID
+	cfg.Discord.HelperRoleID = testHelperRoleID
+	cfg.Discord.TroubleshootingChannelID = testTroubleshootingID
+	cfg.Tickets.ChannelID = testTicketChannelID
+	cfg.Tickets.TranscriptChannelID = testTranscriptChannelID
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	store := db.NewMemoryStore()
+	bot := New(&cfg, store, nil)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		if err := bot.Shutdown(ctx); err != nil {
+			t.Errorf("failed to shut down: %v", err)
+		}
+	})
+
+	client := tempest.NewHTTPClient(tempest.HTTPClientOptions{
+		BaseClientOptions: tempest.BaseClientOptions{
+			Token:            cfg.Discord.Token,
+			ComponentHandler: bot.HandleDynamicComponent,
+		},
+		PublicKey: cfg.Discord.PublicKey,
+	})
+	srv.Attach(&client.BaseClient)
+
+	openTicketButtonIDs := []string{LegacyOpenTicketButtonID}
+	for _, category := range cfg.TicketCategories() {
+		openTicketButtonIDs = append(openTicketButtonIDs, OpenTicketButtonID(category.ID))
+		err = client.RegisterModal(OpenTicketModalID(category.ID), bot.Modal(bot.HandleOpenTicketModal))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = client.RegisterComponent(openTicketButtonIDs, bot.Component(bot.OpenTicketButtonCallback))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.RegisterComponent([]string{ClaimTicketButtonID}, bot.Component(bot.ClaimTicketButtonCallback))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range []tempest.Command{bot.CloseCommand(), bot.ClaimCommand(), bot.AssignCommand()} {
+		err = client.RegisterCommand(bot.Command(cmd))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range []tempest.Snowflake{testTicketChannelID, testTroubleshootingID, testTranscriptChannelID} {
+		srv.AddChannel(types.Channel{ID: id, Type: tempest.GUILD_TEXT_CHANNEL_TYPE, GuildID: testGuildID})
+	}
+	srv.AddUser(testUser)
+	srv.AddUser(testHelper)
+
+	return &testBot{Bot: bot, t: t, store: store, srv: srv, signer: signer, client: client}
+}
+
+// Where the interactions of testUser come from
+func userOrigin() discordtest.Origin {
+	return discordtest.Origin{
+		ApplicationID: testApplicationID,
+		GuildID:       testGuildID,
+		ChannelID:     testTicketChannelID,
+		Member:        &tempest.Member{User: &testUser},
+	}
+}
+
+// Where the interactions of a helper in a channel come from
+func helperOrigin(helper tempest.User, channelID tempest.Snowflake) discordtest.Origin {
+	return discordtest.Origin{
+		ApplicationID: testApplicationID,
+		GuildID:       testGuildID,
+		ChannelID:     channelID,
+		Member:        &tempest.Member{User: &helper, RoleIDs: []tempest.Snowflake{testHelperRoleID}},
+	}
+}
+
+// The initial response to an interaction, as tempest answers Discord's request
+type testResponse struct {
+	Type tempest.ResponseType `json:"type"`
+	Data struct {
+		CustomID string `json:"custom_id"`
+		Content  string `json:"content"`
+	} `json:"data"`
+}
+
+// Send the interaction to the bot like Discord would, and return its initial response
+func (b *testBot) send(itx tempest.Interaction) testResponse {
+	b.t.Helper()
+
+	w, err := b.signer.Send(b.client.DiscordRequestHandler, itx)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	return decodeResponse(b.t, w)
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) testResponse {
+	t.Helper()
+
+	var response testResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("the bot answered %d %q: %v", w.Code, w.Body.String(), err)
+	}
+
+	return response
+}
+
+// A text input of a submitted modal, as Discord sends it
+func textInput(customID, value string) tempest.LayoutComponent {
+	return tempest.LabelComponent{
+		Type: tempest.LABEL_COMPONENT_TYPE,
+		Component: tempest.TextInputComponent{
+			Type:     tempest.TEXT_INPUT_COMPONENT_TYPE,
+			CustomID: customID,
+			Value:    value,
+		},
+	}
+}
+
+// Open a password ticket for testUser through the Open Ticket button and its intake form, and return its thread
+// once the bot finished setting it up
+func (b *testBot) openTicket() types.Channel {
+	b.t.Helper()
+
+	response := b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	if response.Type != tempest.MODAL_RESPONSE_TYPE {
+		b.t.Fatalf("the Open Ticket button answered %+v, want the intake form", response)
+	}
+
+	before := len(b.srv.Threads(testTicketChannelID))
+	b.send(discordtest.ModalInteraction(userOrigin(), OpenTicketModalID(config.PASSWORD_CATEGORY),
+		textInput("intake-username", "player123"),
+		textInput("intake-platform", "Android, Chrome"),
+	))
+
+	var thread types.Channel
+	opened := b.srv.Wait(testTimeout, func() bool {
+		threads := b.srv.Threads(testTicketChannelID)
+		if len(threads) != before+1 {
+			return false
+		}
+		thread = threads[len(threads)-1]
+		return len(b.srv.Messages(thread.ID)) > 0
+	})
+	if !opened {
+		b.t.Fatal("the ticket thread was not set up")
+	}
+
+	return thread
+}
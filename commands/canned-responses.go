@@ -0,0 +1,337 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Return the options to send a canned response with through SayCommandTemplate
+func cannedResponseSayOptions(r responses.CannedResponse) utils.SayOptions {
+	return utils.SayOptions{
+		ImageURL:         r.Image,
+		ImageDescription: r.ImageDescription,
+		NoPingByDefault:  !r.PingByDefault(),
+	}
+}
+
+// Send the translation of a canned response closest to `locale` to the current ticket thread.
+// If `locale` is empty, the locale of the ticket's user is used.
+// `params` are the values of the response's parameters; the built in variables are filled in from the ticket.
+func (b *Bot) sendCannedResponse(itx *tempest.CommandInteraction, r responses.CannedResponse, locale tempest.Language, params responses.Vars) {
+	vars := responses.Vars{responses.VAR_HELPER: responses.Text(utils.DisplayName(itx.Member))}
+
+	ticket, err := b.Store.GetThreadTicket(itx.ChannelID)
+	switch {
+	case err == nil:
+		vars[responses.VAR_USER] = responses.Raw("<@" + ticket.UserID.String() + ">")
+		vars[responses.VAR_TICKET_NUMBER] = responses.Text(strconv.FormatInt(ticket.Number, 10))
+		vars[responses.VAR_TICKET_AGE] = responses.Raw(fmt.Sprintf("<t:%d:R>", ticket.OpenedAt.Unix()))
+		if locale == "" {
+			locale = ticket.Locale
+		}
+	case err != sql.ErrNoRows:
+		// SayCommandTemplate reports problems with the thread, so just send the response without ticket details
+		logging.For(itx.Interaction).Error("failed to fetch ticket for canned response", "error", err)
+	}
+	maps.Copy(vars, params)
+
+	r, locale = r.Localized(locale)
+
+	opts := cannedResponseSayOptions(r)
+	opts.Locale = locale
+
+	invokerResponse := r.InvokerResponse
+	if locale != i18n.DEFAULT_LOCALE {
+		invokerResponse += fmt.Sprintf(" (Sent in %s.)", i18n.LocaleNames[locale])
+	}
+
+	b.say(itx, responses.Render(r.Body, vars), invokerResponse, opts)
+}
+
+// Maximum length of a parameter's value, so the rendered message stays within Discord's limits
+const maxParameterLength = 100
+
+// Build the slash command sending a canned response to the current ticket thread
+func (b *Bot) CannedResponseCommand(r responses.CannedResponse) tempest.Command {
+	opts := cannedResponseSayOptions(r)
+
+	// Discord requires required options to come before optional ones
+	var options []tempest.CommandOption
+	for _, required := range []bool{true, false} {
+		for _, p := range r.Parameters {
+			if p.Required == required {
+				options = append(options, tempest.CommandOption{
+					Type:        tempest.STRING_OPTION_TYPE,
+					Name:        p.Name,
+					Description: p.Description,
+					Required:    p.Required,
+					MaxLength:   maxParameterLength,
+				})
+			}
+		}
+	}
+
+	if opts.NoPingByDefault {
+		options = append(options, PING_OPTION)
+	} else {
+		options = append(options, NO_PING_OPTION)
+	}
+
+	return tempest.Command{
+		Name:                r.Name,
+		Description:         r.Description,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Options:             options,
+		SlashCommandHandler: func(itx *tempest.CommandInteraction) {
+			// Look the response up again, as it may have been edited or deleted with /response since
+			r, ok := responses.Find(r.Name)
+			if !ok {
+				itx.SendLinearReply("This canned response has been deleted.", true)
+				return
+			}
+
+			params := make(responses.Vars, len(r.Parameters))
+			for _, p := range r.Parameters {
+				// Optional parameters the helper left out are rendered empty
+				value, _ := utils.GetOption[string](itx, p.Name, false)
+				params[p.Name] = responses.Text(value)
+			}
+
+			b.sendCannedResponse(itx, r, "", params)
+		},
+		Contexts: []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
+}
+
+// LoadCannedResponses loads the canned responses in the file at `path`, applies the changes helpers saved with
+// /response on top of them, and registers a slash command for each. The commands still need to be synced with Discord.
+func (b *Bot) LoadCannedResponses(client *tempest.BaseClient, path string) error {
+	loaded, err := responses.Load(path)
+	if err != nil {
+		return err
+	}
+
+	b.cannedCommandsMu.Lock()
+	defer b.cannedCommandsMu.Unlock()
+
+	b.fileResponses = loaded
+	return b.reloadCannedResponses(client)
+}
+
+// Return the canned responses from the file, replaced, added to or deleted by the latest revisions saved with /response.
+// Revisions named like another command are left out.
+// Must be called with b.cannedCommandsMu held.
+func (b *Bot) mergeCannedResponses(client *tempest.BaseClient) ([]responses.CannedResponse, error) {
+	revisions, err := b.Store.GetCannedResponses()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]responses.CannedResponse, len(b.fileResponses)+len(revisions))
+	var names []string
+	for _, r := range b.fileResponses {
+		byName[r.Name] = r
+		names = append(names, r.Name)
+	}
+
+	for _, revision := range revisions {
+		// A command added after the revision was saved, e.g. by a new release of the bot, keeps its name
+		if b.isOtherCommand(client, revision.Name) {
+			slog.Warn("ignoring canned response saved with /response, its name is taken by another command",
+				"response", revision.Name, "revision", revision.Revision)
+			continue
+		}
+
+		if _, ok := byName[revision.Name]; !ok {
+			names = append(names, revision.Name)
+		}
+
+		if revision.Deleted() {
+			delete(byName, revision.Name)
+			continue
+		}
+
+		var r responses.CannedResponse
+		err = json.Unmarshal([]byte(revision.Data), &r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse revision %d of canned response %q: %w", revision.Revision, revision.Name, err)
+		}
+		byName[revision.Name] = r
+	}
+
+	merged := make([]responses.CannedResponse, 0, len(byName))
+	for _, name := range names {
+		if r, ok := byName[name]; ok {
+			merged = append(merged, r)
+		}
+	}
+
+	return merged, nil
+}
+
+// Make the merged canned responses active, and register a command for those that do not have one yet.
+// Must be called with b.cannedCommandsMu held.
+func (b *Bot) reloadCannedResponses(client *tempest.BaseClient) error {
+	merged, err := b.mergeCannedResponses(client)
+	if err != nil {
+		return err
+	}
+
+	err = responses.Validate(merged)
+	if err != nil {
+		return err
+	}
+	responses.SetActive(merged)
+
+	for _, r := range merged {
+		if b.cannedCommands[r.Name] {
+			continue
+		}
+
+		err = client.RegisterCommand(b.Command(b.CannedResponseCommand(r)))
+		if err != nil {
+			return fmt.Errorf("failed to register canned response %q: %w", r.Name, err)
+		}
+		b.cannedCommands[r.Name] = true
+	}
+
+	return nil
+}
+
+// SyncCommands sends every registered slash command to Discord, leaving out the canned responses deleted with /response
+func (b *Bot) SyncCommands(client *tempest.BaseClient, guildID tempest.Snowflake) error {
+	b.cannedCommandsMu.Lock()
+	defer b.cannedCommandsMu.Unlock()
+
+	// Tempest cannot unregister commands, so deleted canned responses stay registered and are left out of the sync instead
+	var deleted []string
+	for name := range b.cannedCommands {
+		if _, ok := responses.Find(name); !ok {
+			deleted = append(deleted, name)
+		}
+	}
+
+	err := client.SyncCommandsWithDiscord([]tempest.Snowflake{guildID}, deleted, true)
+	if err != nil {
+		return err
+	}
+
+	b.commandsSynced.Store(true)
+	return nil
+}
+
+// CommandsSynced returns an error until SyncCommands succeeded once
+func (b *Bot) CommandsSynced() error {
+	if !b.commandsSynced.Load() {
+		return errors.New("commands were not synced with Discord yet")
+	}
+
+	return nil
+}
+
+// A command registered with Discord, as listed by Discord
+type guildCommand struct {
+	ID   tempest.Snowflake   `json:"id"`
+	Type tempest.CommandType `json:"type"`
+	Name string              `json:"name"`
+}
+
+// Send the current definition of a canned response's command to Discord after it was created, edited or deleted
+// with /response. Only that command is sent, as re-sending every command makes them all flicker in the Discord UI.
+// Tempest keeps the definition a command was registered with and cannot unregister commands, so this goes straight
+// to the API instead of through SyncCommandsWithDiscord.
+// Must be called with b.cannedCommandsMu held.
+// https://discord.com/developers/docs/interactions/application-commands#edit-guild-application-command
+func (b *Bot) syncCannedResponse(client *tempest.BaseClient, guildID tempest.Snowflake, name string) error {
+	route := "/applications/" + client.ApplicationID.String() + "/guilds/" + guildID.String() + "/commands"
+
+	body, err := client.Rest.Request(http.MethodGet, route, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list the commands: %w", err)
+	}
+	var registered []guildCommand
+	err = json.Unmarshal(body, &registered)
+	if err != nil {
+		return fmt.Errorf("failed to parse the commands: %w", err)
+	}
+
+	var id tempest.Snowflake
+	for _, cmd := range registered {
+		if cmd.Name == name && cmd.Type == tempest.CHAT_INPUT_COMMAND_TYPE {
+			id = cmd.ID
+		}
+	}
+
+	r, active := responses.Find(name)
+	switch {
+	case !active && id == 0:
+		return nil
+	case !active:
+		_, err = client.Rest.Request(http.MethodDelete, route+"/"+id.String(), nil)
+	case id == 0:
+		_, err = client.Rest.Request(http.MethodPost, route, b.cannedResponseCommandDefinition(client, r))
+	default:
+		_, err = client.Rest.Request(http.MethodPatch, route+"/"+id.String(), b.cannedResponseCommandDefinition(client, r))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update canned response %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// The command of a canned response, as sent to Discord
+func (b *Bot) cannedResponseCommandDefinition(client *tempest.BaseClient, r responses.CannedResponse) tempest.Command {
+	cmd := b.CannedResponseCommand(r)
+	cmd.ApplicationID = client.ApplicationID
+	cmd.Type = tempest.CHAT_INPUT_COMMAND_TYPE
+	return cmd
+}
+
+// Save a new revision of a canned response, or its deletion if `r` is nil, then reload the canned responses
+// and send its command to Discord.
+func (b *Bot) saveCannedResponse(client *tempest.BaseClient, name string, r *responses.CannedResponse, authorID tempest.Snowflake) (int64, error) {
+	b.cannedCommandsMu.Lock()
+	defer b.cannedCommandsMu.Unlock()
+
+	var data []byte
+	if r != nil {
+		var err error
+		data, err = json.Marshal(r)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	revision, err := b.Store.SaveCannedResponseRevision(name, string(data), authorID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = b.reloadCannedResponses(client)
+	if err != nil {
+		return revision, err
+	}
+
+	return revision, b.syncCannedResponse(client, b.Config.Discord.GuildID, name)
+}
@@ -0,0 +1,286 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pagefaultgames/ticketune/discordtest"
+	"github.com/pagefaultgames/ticketune/responses"
+
+	"github.com/amatsagu/tempest"
+)
+
+const testCannedResponses = `[{
+	"name": "greet",
+	"description": "Greet the user",
+	"body": "Hello {user}!",
+	"invoker_response": "The user was greeted.",
+	"translations": {"de": {"body": "Hallo {user}!"}}
+}]`
+
+// Load testCannedResponses and sync the commands with the fake Discord API, like the bot does when it starts
+func (b *testBot) loadCannedResponses() {
+	b.t.Helper()
+
+	path := filepath.Join(b.t.TempDir(), "responses.json")
+	err := os.WriteFile(path, []byte(testCannedResponses), 0o600)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	err = b.LoadCannedResponses(&b.client.BaseClient, path)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	err = b.SyncCommands(&b.client.BaseClient, testGuildID)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+}
+
+// Return the command registered in the test guild with the given name
+func (b *testBot) guildCommand(name string) (discordtest.Command, bool) {
+	for _, cmd := range b.srv.Commands(testGuildID) {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+
+	return discordtest.Command{}, false
+}
+
+// Return the methods of the requests made to the guild's commands since `since` requests were made
+func (b *testBot) commandRequests(since int) []string {
+	route := "/applications/" + testApplicationID.String() + "/guilds/" + testGuildID.String() + "/commands"
+
+	var methods []string
+	for _, r := range b.srv.Requests()[since:] {
+		if strings.HasPrefix(r.Path, route) {
+			methods = append(methods, r.Method)
+		}
+	}
+
+	return methods
+}
+
+// Editing, creating and deleting a canned response only sends its own command to Discord, in a single write
+func TestSaveCannedResponseSyncsOneCommand(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.loadCannedResponses()
+	client := &b.client.BaseClient
+
+	before, ok := b.guildCommand("greet")
+	if !ok {
+		t.Fatalf("greet was not synced, commands: %+v", b.srv.Commands(testGuildID))
+	}
+	total := len(b.srv.Commands(testGuildID))
+
+	r, _ := responses.Find("greet")
+	r.Description = "Say hello to the user"
+	r.Parameters = []responses.Parameter{{Name: "topic", Description: "What the user needs help with"}}
+	since := len(b.srv.Requests())
+	_, err := b.saveCannedResponse(client, r.Name, &r, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if methods := b.commandRequests(since); len(methods) != 2 || methods[1] != http.MethodPatch {
+		t.Errorf("editing a canned response made the requests %v, want a single PATCH after listing the commands", methods)
+	}
+	after, _ := b.guildCommand("greet")
+	if after.ID != before.ID || after.Description != r.Description || len(after.Options) != 2 || after.Options[0].Name != "topic" {
+		t.Errorf("greet is %+v after editing it, want the same command with the new description and parameter", after)
+	}
+
+	created := responses.CannedResponse{Name: "bye", Description: "Say goodbye", Body: "Bye!", InvokerResponse: "Said goodbye."}
+	since = len(b.srv.Requests())
+	_, err = b.saveCannedResponse(client, created.Name, &created, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if methods := b.commandRequests(since); len(methods) != 2 || methods[1] != http.MethodPost {
+		t.Errorf("creating a canned response made the requests %v, want a single POST after listing the commands", methods)
+	}
+	if _, ok := b.guildCommand("bye"); !ok || len(b.srv.Commands(testGuildID)) != total+1 {
+		t.Errorf("bye was not added to the commands: %+v", b.srv.Commands(testGuildID))
+	}
+
+	since = len(b.srv.Requests())
+	_, err = b.saveCannedResponse(client, "greet", nil, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if methods := b.commandRequests(since); len(methods) != 2 || methods[1] != http.MethodDelete {
+		t.Errorf("deleting a canned response made the requests %v, want a single DELETE after listing the commands", methods)
+	}
+	if _, ok := b.guildCommand("greet"); ok || len(b.srv.Commands(testGuildID)) != total {
+		t.Errorf("greet is still registered after deleting it: %+v", b.srv.Commands(testGuildID))
+	}
+}
+
+// The commands sent when the bot starts leave out the canned responses deleted with /response
+func TestSyncCommandsLeavesOutDeletedResponses(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.loadCannedResponses()
+
+	_, err := b.saveCannedResponse(&b.client.BaseClient, "greet", nil, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.SyncCommands(&b.client.BaseClient, testGuildID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd, ok := b.guildCommand("greet"); ok {
+		t.Errorf("the deleted canned response was synced: %+v", cmd)
+	}
+	if _, ok := b.guildCommand("close"); !ok {
+		t.Errorf("the other commands were not synced: %+v", b.srv.Commands(testGuildID))
+	}
+}
+
+// Editing the message of a canned response from Discord removes its outdated translations, and says so
+func TestEditCannedResponseDropsStaleTranslations(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.loadCannedResponses()
+
+	err := b.client.RegisterCommand(ResponseCommandGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.client.RegisterSubCommand(b.Command(b.ResponseEdit()), ResponseCommandGroup.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.client.RegisterModal(CannedResponseModalID, b.Modal(b.HandleCannedResponseModal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origin := helperOrigin(testHelper, testTicketChannelID)
+	response := b.send(discordtest.CommandInteraction(origin, ResponseCommandGroup.Name, tempest.CommandInteractionOption{
+		Type:    tempest.SUB_OPTION_TYPE,
+		Name:    "edit",
+		Options: []tempest.CommandInteractionOption{discordtest.Option("response", "greet")},
+	}))
+	if response.Type != tempest.MODAL_RESPONSE_TYPE {
+		t.Fatalf("/response edit answered %+v, want the form", response)
+	}
+
+	submit := discordtest.ModalInteraction(origin, CannedResponseModalID,
+		textInput("description", "Greet the user"),
+		textInput("body", "Welcome {user}!"),
+		textInput("invoker-response", "The user was greeted."),
+		textInput("image", ""),
+		textInput("image-description", ""),
+	)
+	// The form is deferred, then answered with a follow-up
+	_, err = b.signer.Send(b.client.DiscordRequestHandler, submit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reply string
+	replied := b.srv.Wait(testTimeout, func() bool {
+		followUps := b.srv.InteractionResponses(submit.Token)
+		if len(followUps) == 0 {
+			return false
+		}
+		reply = string(followUps[len(followUps)-1])
+		return true
+	})
+	if !replied {
+		t.Fatal("the form was not answered")
+	}
+
+	r, _ := responses.Find("greet")
+	if r.Body != "Welcome {user}!" || len(r.Translations) != 0 {
+		t.Errorf("greet is %+v after editing its message, want no translations", r)
+	}
+	if !strings.Contains(reply, "(de)") || !strings.Contains(reply, "only be edited in the JSON file") {
+		t.Errorf("the edit was answered %s, want the removed translations and where to edit them", reply)
+	}
+}
+
+// A canned response saved with /response whose name was since taken by another command is left out, instead of
+// stopping the bot from starting
+func TestLoadCannedResponsesSkipsTakenNames(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	_, err := b.store.SaveCannedResponseRevision("close", `{"name": "close", "description": "Close", "body": "Bye!", "invoker_response": "Said bye."}`, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.loadCannedResponses()
+
+	if r, ok := responses.Find("close"); ok {
+		t.Errorf("the canned response named like /close was loaded: %+v", r)
+	}
+	if _, ok := responses.Find("greet"); !ok {
+		t.Error("the other canned responses were not loaded")
+	}
+}
+
+// The name of a new canned response is checked again when its form is submitted, as a command may have been
+// registered with it since the form was opened
+func TestCreateCannedResponseNameTakenMeanwhile(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.loadCannedResponses()
+
+	err := b.client.RegisterCommand(ResponseCommandGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.client.RegisterSubCommand(b.Command(b.ResponseCreate()), ResponseCommandGroup.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.client.RegisterModal(CannedResponseModalID, b.Modal(b.HandleCannedResponseModal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origin := helperOrigin(testHelper, testTicketChannelID)
+	response := b.send(discordtest.CommandInteraction(origin, ResponseCommandGroup.Name, tempest.CommandInteractionOption{
+		Type:    tempest.SUB_OPTION_TYPE,
+		Name:    "create",
+		Options: []tempest.CommandInteractionOption{discordtest.Option("name", "bye")},
+	}))
+	if response.Type != tempest.MODAL_RESPONSE_TYPE {
+		t.Fatalf("/response create answered %+v, want the form", response)
+	}
+
+	err = b.client.RegisterCommand(b.Command(tempest.Command{Name: "bye", SlashCommandHandler: func(*tempest.CommandInteraction) {}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response = b.send(discordtest.ModalInteraction(origin, CannedResponseModalID,
+		textInput("description", "Say goodbye"),
+		textInput("body", "Bye!"),
+		textInput("invoker-response", "Said goodbye."),
+		textInput("image", ""),
+		textInput("image-description", ""),
+	))
+	if !strings.Contains(response.Data.Content, "already a command") {
+		t.Errorf("the form was answered %q, want the name refused", response.Data.Content)
+	}
+
+	revisions, err := b.store.GetCannedResponses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 0 {
+		t.Errorf("the canned response was saved: %+v", revisions)
+	}
+}
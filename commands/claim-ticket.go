@@ -0,0 +1,295 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/types"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Custom ID of the "Claim" button on the ticket instructions message
+const ClaimTicketButtonID = "claim-ticket-button"
+
+func (b *Bot) ClaimCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "claim",
+		Description:         "Claim the current ticket, marking you as the helper working on it",
+		SlashCommandHandler: b.claimCommandImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
+}
+
+func (b *Bot) UnclaimCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "unclaim",
+		Description:         "Release the current ticket so another helper can pick it up",
+		SlashCommandHandler: b.unclaimCommandImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
+}
+
+func (b *Bot) AssignCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "assign",
+		Description:         "Assign the current ticket to a helper",
+		SlashCommandHandler: b.assignCommandImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{{
+			Type:        tempest.USER_OPTION_TYPE,
+			Name:        "helper",
+			Description: "The helper to assign the ticket to",
+			Required:    true,
+		}},
+	}
+}
+
+const notATicketThreadMessage = "This command can only be used in a ticket thread"
+const noTicketInThreadMessage = "I couldn't find an open ticket for this thread in my database."
+
+// Return whether the channel is a ticket thread
+func (b *Bot) isTicketThread(logger *slog.Logger, client *tempest.BaseClient, channelID tempest.Snowflake) bool {
+	channel, err := utils.GetChannelFromID(client, channelID)
+	if err != nil {
+		logger.Error("failed to fetch channel", "error", err)
+		return false
+	}
+
+	return b.isTicketChannel(channel)
+}
+
+// Rename the thread of a ticket just assigned to `helper` (or unassigned if `helper` is nil) to show who is working on it.
+// Failing to rename the thread (e.g. due to Discord's rate limit on renames) does not undo the assignment.
+func (b *Bot) showTicketAssignee(logger *slog.Logger, client *tempest.BaseClient, ticket db.Ticket, helper *tempest.Member) {
+	err := b.renameTicketThread(client, ticket, utils.DisplayName(helper))
+	if err != nil {
+		logger.Warn("failed to rename ticket thread", logging.Ticket(ticket.Number), "error", err)
+	}
+}
+
+// Rename the ticket thread to include the name of the helper working on it, if any
+// https://discord.com/developers/docs/resources/channel#modify-channel
+func (b *Bot) renameTicketThread(client *tempest.BaseClient, ticket db.Ticket, helperName string) error {
+	// The thread is named after the category's pattern, which is unknown if the category was removed
+	category, ok := b.Config.TicketCategory(ticket.Category)
+	if !ok {
+		return fmt.Errorf("unknown ticket category %q", ticket.Category)
+	}
+
+	user, err := client.FetchUser(ticket.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Rest.Request(
+		http.MethodPatch,
+		fmt.Sprintf("/channels/%d", ticket.ThreadID),
+		types.ModifyThreadParams{Name: ticketThreadName(category.ThreadName, user.Username, helperName)},
+	)
+
+	return err
+}
+
+// Message announcing the new assignee, sent publicly in the thread so the user knows who is helping them
+func assignedMessage(helper *tempest.Member) tempest.ResponseMessageData {
+	return tempest.ResponseMessageData{
+		Content: fmt.Sprintf("**%s** is now handling this ticket.", responses.EscapeMarkdown(utils.DisplayName(helper))),
+		// Don't ping the helper or the user
+		AllowedMentions: &tempest.AllowedMentions{},
+	}
+}
+
+// Claim the ticket for `helper`, unless another helper already claimed it.
+// Returns the message to reply with, and whether the claim succeeded
+func (b *Bot) claimTicket(itx *tempest.Interaction, helper *tempest.Member) (string, bool) {
+	logger := logging.For(itx)
+
+	ticket, err := b.Store.ClaimTicket(itx.ChannelID, helper.User.ID)
+	switch {
+	case err == sql.ErrNoRows:
+		return noTicketInThreadMessage, false
+	case err == db.ErrTicketClaimed && ticket.Assignee == helper.User.ID:
+		return "You have already claimed this ticket.", false
+	case err == db.ErrTicketClaimed:
+		return fmt.Sprintf("This ticket has already been claimed by <@%d>. Use `/assign` to take it over.", ticket.Assignee), false
+	case err != nil:
+		logger.Error("failed to claim ticket", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to claim a ticket",
+			Err:         err,
+			Interaction: itx,
+		})
+		return "Something went wrong while claiming this ticket.", false
+	}
+
+	b.showTicketAssignee(logging.WithTicket(itx, ticket.Number), itx.Client, ticket, helper)
+	return "", true
+}
+
+// Handle the "Claim" button on the ticket instructions message
+func (b *Bot) ClaimTicketButtonCallback(itx tempest.ComponentInteraction) {
+	// The button is visible to the ticket's user as well, so make sure only helpers can press it
+	if !b.isHelper(itx.Member) {
+		itx.AcknowledgeWithLinearMessage("Only helpers can claim tickets.", true)
+		return
+	}
+
+	reply, ok := b.claimTicket(itx.Interaction, itx.Member)
+	if !ok {
+		itx.AcknowledgeWithLinearMessage(reply, true)
+		return
+	}
+
+	itx.AcknowledgeWithMessage(assignedMessage(itx.Member), false)
+}
+
+func (b *Bot) claimCommandImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	if !b.isTicketThread(logging.For(itx.Interaction), itx.Client, itx.ChannelID) {
+		itx.SendLinearReply(notATicketThreadMessage, true)
+		return
+	}
+
+	reply, ok := b.claimTicket(itx.Interaction, itx.Member)
+	if !ok {
+		itx.SendLinearReply(reply, true)
+		return
+	}
+
+	itx.SendReply(assignedMessage(itx.Member), false, nil)
+}
+
+func (b *Bot) unclaimCommandImpl(itx *tempest.CommandInteraction) {
+	logger := logging.For(itx.Interaction)
+
+	if !b.isTicketThread(logger, itx.Client, itx.ChannelID) {
+		itx.SendLinearReply(notATicketThreadMessage, true)
+		return
+	}
+
+	ticket, err := b.Store.GetThreadTicket(itx.ChannelID)
+	if err == sql.ErrNoRows {
+		itx.SendLinearReply(noTicketInThreadMessage, true)
+		return
+	} else if err != nil {
+		logger.Error("failed to fetch ticket from database", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to look up a ticket to unclaim it",
+			Err:         err,
+			Interaction: itx.Interaction,
+		})
+		itx.SendLinearReply("Something went wrong while looking up this ticket in my database.", true)
+		return
+	}
+	logger = logging.WithTicket(itx.Interaction, ticket.Number)
+
+	assignee := ticket.Assignee
+	if assignee == 0 {
+		itx.SendLinearReply("Nobody has claimed this ticket.", true)
+		return
+	}
+
+	ticket, err = b.Store.UnclaimTicket(itx.ChannelID, assignee)
+	if err == sql.ErrNoRows {
+		itx.SendLinearReply(noTicketInThreadMessage, true)
+		return
+	} else if err == db.ErrTicketClaimed {
+		// Another helper claimed, unclaimed or was assigned the ticket in the meantime
+		itx.SendLinearReply("This ticket's assignee just changed. Check who is handling it before trying again.", true)
+		return
+	} else if err != nil {
+		logger.Error("failed to unassign ticket", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to unclaim a ticket",
+			Err:         err,
+			Interaction: itx.Interaction,
+			Ticket:      ticket.Number,
+		})
+		itx.SendLinearReply("Something went wrong while unclaiming this ticket.", true)
+		return
+	}
+	b.showTicketAssignee(logger, itx.Client, ticket, nil)
+
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         fmt.Sprintf("<@%d> is no longer handling this ticket. Another helper will pick it up soon.", assignee),
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, false, nil)
+}
+
+func (b *Bot) assignCommandImpl(itx *tempest.CommandInteraction) {
+	helperIDStr, err := utils.GetOption[string](itx, "helper", true)
+	if err != nil {
+		return
+	}
+
+	helperID, err := tempest.StringToSnowflake(helperIDStr)
+	if err != nil {
+		itx.SendLinearReply("Invalid user ID", true)
+		return
+	}
+
+	// Discord includes the member in the resolved data of user options used in guilds
+	var helper tempest.Member
+	if itx.Data.Resolved != nil {
+		helper = itx.ResolveMember(helperID)
+	}
+	if helper.User == nil {
+		itx.SendLinearReply("That user is not a member of this server.", true)
+		return
+	}
+
+	if !b.isHelper(&helper) {
+		itx.SendLinearReply(fmt.Sprintf("<@%d> is not a helper.", helperID), true)
+		return
+	}
+
+	logger := logging.For(itx.Interaction)
+	if !b.isTicketThread(logger, itx.Client, itx.ChannelID) {
+		itx.SendLinearReply(notATicketThreadMessage, true)
+		return
+	}
+
+	ticket, err := b.Store.SetTicketAssignee(itx.ChannelID, helperID)
+	if err == sql.ErrNoRows {
+		itx.SendLinearReply(noTicketInThreadMessage, true)
+		return
+	} else if err != nil {
+		logger.Error("failed to assign ticket", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to assign a ticket",
+			Err:         err,
+			Interaction: itx.Interaction,
+		})
+		itx.SendLinearReply("Something went wrong while assigning this ticket.", true)
+		return
+	}
+	b.showTicketAssignee(logging.WithTicket(itx.Interaction, ticket.Number), itx.Client, ticket, &helper)
+
+	itx.SendReply(assignedMessage(&helper), false, nil)
+}
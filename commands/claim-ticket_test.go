@@ -0,0 +1,88 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pagefaultgames/ticketune/discordtest"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Two helpers pressing the Claim button at once can't both claim the ticket
+func TestClaimTicketButtonRace(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+
+	helpers := []tempest.User{testHelper, {ID: 202, Username: "other-helper"}}
+	responses := make([]testResponse, len(helpers))
+	var wg sync.WaitGroup
+	for i, helper := range helpers {
+		wg.Go(func() {
+			w, err := b.signer.Send(b.client.DiscordRequestHandler, discordtest.ComponentInteraction(helperOrigin(helper, thread.ID), ClaimTicketButtonID))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			responses[i] = decodeResponse(t, w)
+		})
+	}
+	wg.Wait()
+
+	ticket, err := b.store.GetThreadTicket(thread.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed := 0
+	for i, response := range responses {
+		switch {
+		case strings.Contains(response.Data.Content, "is now handling this ticket"):
+			claimed++
+			if ticket.Assignee != helpers[i].ID {
+				t.Errorf("%s claimed the ticket, but it is assigned to %d", helpers[i].Username, ticket.Assignee)
+			}
+		case !strings.Contains(response.Data.Content, "already been claimed"):
+			t.Errorf("%s's claim was answered %q", helpers[i].Username, response.Data.Content)
+		}
+	}
+	if claimed != 1 {
+		t.Errorf("%d helpers claimed the ticket, want 1", claimed)
+	}
+}
+
+func TestClaimClaimedTicket(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+
+	_, err := b.store.ClaimTicket(thread.ID, 202)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := b.send(discordtest.ComponentInteraction(helperOrigin(testHelper, thread.ID), ClaimTicketButtonID))
+	if !strings.Contains(response.Data.Content, "already been claimed by <@202>") {
+		t.Errorf("claiming a claimed ticket answered %q", response.Data.Content)
+	}
+}
+
+// The helper's display name is shown as typed, without formatting or pings
+func TestClaimTicketEscapesDisplayName(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+
+	origin := helperOrigin(testHelper, thread.ID)
+	origin.Member.Nickname = "**boss** @everyone"
+	response := b.send(discordtest.ComponentInteraction(origin, ClaimTicketButtonID))
+	if want := `**\*\*boss\*\* \@everyone** is now handling this ticket.`; response.Data.Content != want {
+		t.Errorf("claiming the ticket answered %q, want %q", response.Data.Content, want)
+	}
+}
@@ -10,78 +10,180 @@ package commands
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 
-	"github.com/pagefaultgames/ticketune/constants"
+	"github.com/pagefaultgames/ticketune/alerts"
 	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/types"
 	"github.com/pagefaultgames/ticketune/utils"
 
 	"github.com/amatsagu/tempest"
 )
 
-var closeCommandDescription = "Close the current password ticket thread and remove the associated user's permission overrides"
+var closeCommandDescription = "Close the current ticket thread and remove the associated user's permission overrides"
 
-var CloseCommand = tempest.Command{
-	Name:                "close",
-	Description:         closeCommandDescription,
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	SlashCommandHandler: closeTicketCommandImpl,
-	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+func (b *Bot) CloseCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "close",
+		Description:         closeCommandDescription,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		SlashCommandHandler: b.closeTicketCommandImpl,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
 }
 
-func closeTicketCommandImpl(itx *tempest.CommandInteraction) {
-	// If this is not a thread in the ticket channel, do nothing
+func (b *Bot) closeTicketCommandImpl(itx *tempest.CommandInteraction) {
+	logger := logging.For(itx.Interaction)
+
+	// If this is not a thread in a ticket channel, do nothing
 	channel, err := utils.GetChannelFromID(itx.Client, itx.ChannelID)
 	if err != nil {
-		log.Println("Error fetching channel info:", err)
+		logger.Error("failed to fetch channel", "error", err)
 		//return // will be caught by next check
 	}
 
 	// ParentID is the ID of the parent channel for threads, or the category ID for channels
-	// If ParentID is not the ticket channel ID, this is not a valid ticket thread
-	if channel.ParentID != constants.TICKET_CHANNEL_ID || channel.Type != tempest.GUILD_PRIVATE_THREAD_CHANNEL_TYPE {
-		itx.SendLinearReply("This command can only be used on a password ticket thread", true)
+	// If ParentID is not one of the ticket channel IDs, this is not a valid ticket thread
+	if !b.isTicketChannel(channel) {
+		itx.SendLinearReply("This command can only be used on a ticket thread", true)
 		return
 	}
 
-	user, err := db.Get().CloseThread(itx.ChannelID)
+	// Paging through the thread's messages for the transcript can take longer than Discord's 3 second limit
+	err = itx.Defer(true)
+	if err != nil {
+		logger.Error("failed to defer close command", "error", err)
+	}
+
+	// Record who closed the ticket. Member is always present, as the command can only be used in guilds
+	var closedBy tempest.Snowflake
+	if itx.Member != nil && itx.Member.User != nil {
+		closedBy = itx.Member.User.ID
+	}
+
+	ticket, err := b.Store.GetThreadTicket(itx.ChannelID)
 	if err == sql.ErrNoRows {
 		// If no rows were returned, tell the initiator of the commands.
 		itx.SendLinearReply("Error: I couldn't find a user associated with this thread in my database. You'll have to close the thread manually.", true)
 		return
+	} else if err != nil {
+		logger.Error("failed to fetch ticket from database", "error", err)
+		itx.SendLinearReply("Error: Something went wrong while looking up this ticket in my database. You'll have to close the thread manually.", true)
+		return
 	}
+	logger = logging.WithTicket(itx.Interaction, ticket.Number)
 
-	// Delete the channel permissions for the user
-	err = deleteChannelPermissionForUser(itx.Client, user)
+	err = b.closeTicket(logger, itx.Client, channel, ticket, closedBy)
 	if err != nil {
-		log.Println("Error deleting channel permission for user:", err)
+		alert := closeTicketAlert(ticket, err)
+		alert.Interaction = itx.Interaction
+		b.alert(itx.Client, alert)
+	}
+
+	switch {
+	case errors.Is(err, ErrTranscriptFailed):
+		itx.SendLinearReply("Error: I couldn't save a transcript of this ticket, so I did not close it: "+err.Error(), true)
+	case errors.Is(err, ErrCloseInDatabaseFailed):
+		itx.SendLinearReply("Error: Something went wrong while closing the ticket in my database. You'll have to close the thread manually.", true)
+	case errors.Is(err, ErrRemovePermissionsFailed):
 		itx.SendLinearReply("Error: I couldn't remove the user's permissions to access this thread. You'll have to close the thread manually.", true)
-		return
+	case errors.Is(err, ErrDeleteThreadFailed):
+		// The thread is still there, so replace "thinking..." like the other errors do
+		itx.SendLinearReply("Error: I closed the ticket and removed the user's access to it, but couldn't delete the thread. You'll have to delete it manually.", true)
+		itx.SendLinearFollowUp(
+			fmt.Sprintf("I removed the user's access to the ticket, but ran into an error deleting the thread: %s.",
+				err.Error()),
+			// Not ephemeral so that a Helper can show a dev what went wrong
+			false,
+		)
+	}
+}
+
+var ErrTranscriptFailed = errors.New("failed to save transcript")
+var ErrCloseInDatabaseFailed = errors.New("failed to close ticket in database")
+var ErrRemovePermissionsFailed = errors.New("failed to remove user's permissions")
+var ErrDeleteThreadFailed = errors.New("failed to delete thread")
+
+// Close a ticket: save its transcript, mark it closed in the database, remove the user's permissions and delete the thread.
+// Stops at the first step that fails, returning an error wrapping one of the Err*Failed errors above.
+// `closedBy` is 0 when the bot closes the ticket by itself.
+func (b *Bot) closeTicket(logger *slog.Logger, client *tempest.BaseClient, channel types.Channel, ticket db.Ticket, closedBy tempest.Snowflake) error {
+	// Save the transcript before anything is deleted; it is the only record of the proof of ownership.
+	err := b.saveTranscript(logger, client, channel, ticket, closedBy)
+	if err != nil {
+		logger.Error("failed to save ticket transcript", "error", err)
+		return fmt.Errorf("%w: %w", ErrTranscriptFailed, err)
+	}
+
+	user, err := b.Store.CloseThread(channel.ID, closedBy)
+	if err != nil {
+		logger.Error("failed to close ticket in database", "error", err)
+		return fmt.Errorf("%w: %w", ErrCloseInDatabaseFailed, err)
+	}
+
+	// Delete the channel permissions for the user
+	err = b.deleteChannelPermissionForUser(logger, client, channel.ParentID, user)
+	if err != nil {
+		logger.Error("failed to remove user's ticket channel permissions", "error", err)
+		return fmt.Errorf("%w: %w", ErrRemovePermissionsFailed, err)
 	}
 
 	// Delete the thread
-	_, err = itx.Client.Rest.Request(
+	_, err = client.Rest.Request(
 		http.MethodDelete,
-		fmt.Sprintf("/channels/%d", itx.ChannelID),
+		fmt.Sprintf("/channels/%d", channel.ID),
 		nil,
 	)
 	if err != nil {
-		itx.SendLinearReply(
-			fmt.Sprintf("I removed the user's access to the ticket, but ran into an error deleting the thread: %s.",
-				err.Error()),
-			// Not ephemeral so that a Helper can show a dev what went wrong
-			false,
-		)
+		logger.Error("failed to delete ticket thread", "error", err)
+		return fmt.Errorf("%w: %w", ErrDeleteThreadFailed, err)
 	}
+
+	return nil
 }
 
-// Remove permission overrides for the user in the ticket channel
-func deleteChannelPermissionForUser(client *tempest.BaseClient, userID tempest.Snowflake) error {
-	_, err := client.Rest.Request(
+// Return the alert reporting that closing the ticket failed with `err`, an error returned by closeTicket
+func closeTicketAlert(ticket db.Ticket, err error) alerts.Alert {
+	alert := alerts.Alert{
+		Kind:    alerts.KIND_PERMISSIONS,
+		Summary: "Failed to close a ticket",
+		Fix:     fmt.Sprintf("Finish closing <#%d> by hand.", ticket.ThreadID),
+		Err:     err,
+		Ticket:  ticket.Number,
+	}
+
+	switch {
+	case errors.Is(err, ErrTranscriptFailed):
+		alert.Fix = fmt.Sprintf("<#%d> was left open, as deleting it would lose its messages.", ticket.ThreadID)
+	case errors.Is(err, ErrCloseInDatabaseFailed):
+		alert.Kind = alerts.KIND_DATABASE
+	}
+
+	return alert
+}
+
+// Remove permission overrides for the user in the ticket channel, unless they still have a ticket open in another
+// category whose threads are created in the same channel
+func (b *Bot) deleteChannelPermissionForUser(logger *slog.Logger, client *tempest.BaseClient, channelID, userID tempest.Snowflake) error {
+	// On error, remove the permissions anyway: the user can be given access again, but would otherwise keep it
+	tickets, err := b.Store.GetUserTickets(userID)
+	if err != nil {
+		logger.Error("failed to look up the user's other tickets", "error", err)
+	}
+	for _, ticket := range tickets {
+		category, ok := b.Config.TicketCategory(ticket.Category)
+		if ticket.Status == db.TICKET_OPEN && ok && category.ChannelID == channelID {
+			return nil
+		}
+	}
+
+	_, err = client.Rest.Request(
 		http.MethodDelete,
-		fmt.Sprintf("/channels/%d/permissions/%d", constants.TICKET_CHANNEL_ID, userID),
+		fmt.Sprintf("/channels/%d/permissions/%d", channelID, userID),
 		nil,
 	)
 	if err != nil {
@@ -0,0 +1,109 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/discordtest"
+)
+
+func TestCloseTicket(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+	b.srv.SendMessage(thread.ID, testUser, "here is my screenshot")
+
+	b.send(discordtest.CommandInteraction(helperOrigin(testHelper, thread.ID), "close"))
+
+	closed := b.srv.Wait(testTimeout, func() bool {
+		_, exists := b.srv.Channel(thread.ID)
+		return !exists
+	})
+	if !closed {
+		t.Fatal("the ticket thread was not deleted")
+	}
+
+	if _, err := b.store.GetThreadTicket(thread.ID); err != sql.ErrNoRows {
+		t.Errorf("the ticket is still open: %v", err)
+	}
+	tickets, err := b.store.GetUserTickets(testUser.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := tickets[0]
+	if len(tickets) != 1 || ticket.Status != db.TICKET_CLOSED || ticket.ClosedBy != testHelper.ID {
+		t.Errorf("the user's tickets are %+v after closing theirs, want it closed by the helper", tickets)
+	}
+
+	transcripts := b.srv.Messages(testTranscriptChannelID)
+	if len(transcripts) != 1 || len(transcripts[0].Attachments) == 0 {
+		t.Fatalf("want one transcript message with attachments, got %+v", transcripts)
+	}
+	content, ok := b.srv.File(transcripts[0].Attachments[0].URL)
+	if !ok || !strings.Contains(string(content), "here is my screenshot") {
+		t.Errorf("the transcript does not include the user's reply: %s", content)
+	}
+	channelID, messageID, err := b.store.GetTranscript(ticket.Number)
+	if err != nil || channelID != testTranscriptChannelID || messageID != transcripts[0].ID {
+		t.Errorf("the transcript was saved as %d/%d (%v), want %d/%d", channelID, messageID, err, testTranscriptChannelID, transcripts[0].ID)
+	}
+
+	if _, ok := b.srv.Permissions(testTicketChannelID, testUser.ID); ok {
+		t.Error("the user's access to the ticket channel was not removed")
+	}
+}
+
+func TestCloseOutsideTicketThread(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	response := b.send(discordtest.CommandInteraction(helperOrigin(testHelper, testTicketChannelID), "close"))
+	if response.Data.Content != "This command can only be used on a ticket thread" {
+		t.Errorf("/close answered %+v outside of a ticket thread", response)
+	}
+	if _, exists := b.srv.Channel(testTicketChannelID); !exists {
+		t.Error("the ticket channel was deleted")
+	}
+}
+
+// When the thread can't be deleted, the deferred reply is replaced with the error, and the error is posted publicly
+func TestCloseTicketDeleteThreadFails(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+	b.srv.Fail(http.MethodDelete, "/channels/"+thread.ID.String(), http.StatusForbidden)
+
+	itx := discordtest.CommandInteraction(helperOrigin(testHelper, thread.ID), "close")
+	_, err := b.signer.Send(b.client.DiscordRequestHandler, itx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := "/webhooks/" + testApplicationID.String() + "/" + itx.Token
+	var edited, followedUp bool
+	answered := b.srv.Wait(testTimeout, func() bool {
+		edited, followedUp = false, false
+		for _, r := range b.srv.Requests() {
+			switch {
+			case r.Method == http.MethodPatch && r.Path == webhook+"/messages/@original":
+				edited = strings.Contains(string(r.Body), "couldn't delete the thread")
+			case r.Method == http.MethodPost && r.Path == webhook:
+				followedUp = strings.Contains(string(r.Body), "error deleting the thread")
+			}
+		}
+		return edited && followedUp
+	})
+	if !answered {
+		t.Errorf("the deferred reply was edited: %v, the error was followed up: %v", edited, followedUp)
+	}
+	if _, exists := b.srv.Channel(thread.ID); !exists {
+		t.Error("the thread was deleted")
+	}
+}
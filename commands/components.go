@@ -0,0 +1,45 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/pagefaultgames/ticketune/metrics"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Handlers for components whose custom IDs carry state after a prefix (e.g. "queue:oldest:2").
+// Tempest only matches static components by exact custom ID, so these go through the client's ComponentHandler,
+// which has already acknowledged the interaction with a deferred update by the time the handler runs.
+func (b *Bot) dynamicComponentHandlers() map[string]func(itx *tempest.ComponentInteraction, args []string) {
+	return map[string]func(itx *tempest.ComponentInteraction, args []string){
+		queueComponentPrefix: b.queueComponentHandler,
+	}
+}
+
+// HandleDynamicComponent dispatches a component interaction to the handler registered for its custom ID prefix.
+// Meant to be used as the ComponentHandler of the tempest client.
+func (b *Bot) HandleDynamicComponent(itx *tempest.ComponentInteraction) {
+	parts := strings.Split(itx.Data.CustomID, ":")
+
+	handler, ok := b.dynamicComponentHandlers()[parts[0]]
+	if !ok {
+		slog.Warn("received component interaction with unknown custom ID", "custom_id", itx.Data.CustomID)
+		return
+	}
+
+	// tempest already acknowledged the interaction, so there is no way to tell the user the bot is shutting down
+	// or that the handler panicked.
+	// The state in the custom ID is left out of the metrics.
+	b.handle(itx.Interaction, metrics.INTERACTION_COMPONENT, parts[0], nil, func() {
+		handler(itx, parts[1:])
+	})
+}
@@ -0,0 +1,91 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Limits how many tickets are opened by everyone together, so that helpers are not flooded when many users need help
+// at once. Only counts the tickets opened through this bot since it started: when several bots share the database,
+// each allows cooldown.global_limit tickets.
+type ticketRateLimiter struct {
+	mu     sync.Mutex
+	opened []time.Time // When the tickets of the last interval were opened, oldest first
+}
+
+// Count a ticket opened at `now` if fewer than `limit` were opened in the `interval` before it.
+// Otherwise, returns when the next ticket may be opened.
+func (l *ticketRateLimiter) reserve(now time.Time, limit int, interval time.Duration) (time.Time, bool) {
+	return l.admit(now, limit, interval, true)
+}
+
+// Return whether a ticket may be opened at `now` like reserve, without counting it
+func (l *ticketRateLimiter) available(now time.Time, limit int, interval time.Duration) (time.Time, bool) {
+	return l.admit(now, limit, interval, false)
+}
+
+// Stop counting a ticket reserved at `at` that could not be opened, so that it does not hold others back
+func (l *ticketRateLimiter) release(at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Its reservation may already have expired
+	if i := slices.Index(l.opened, at); i >= 0 {
+		l.opened = slices.Delete(l.opened, i, i+1)
+	}
+}
+
+func (l *ticketRateLimiter) admit(now time.Time, limit int, interval time.Duration, record bool) (time.Time, bool) {
+	if limit <= 0 {
+		return time.Time{}, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for len(l.opened) > 0 && now.Sub(l.opened[0]) >= interval {
+		l.opened = l.opened[1:]
+	}
+	if len(l.opened) >= limit {
+		return l.opened[0].Add(interval), false
+	}
+
+	if record {
+		l.opened = append(l.opened, now)
+	}
+	return time.Time{}, true
+}
+
+// Return when the user may open a ticket in the category again, if they opened their last one in it less than the
+// cooldown before `now`. Tickets are stored with when they were opened, so the cooldown survives restarts.
+func (b *Bot) ticketCooldownEnd(userID tempest.Snowflake, category string, now time.Time) (time.Time, bool, error) {
+	cooldown := b.Config.Cooldown.UserCooldown()
+	if cooldown <= 0 {
+		return time.Time{}, false, nil
+	}
+
+	tickets, err := b.Store.GetUserTickets(userID)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// Tickets are sorted newest first
+	for _, ticket := range tickets {
+		if ticket.Category == category {
+			end := ticket.OpenedAt.Add(cooldown)
+			return end, now.Before(end), nil
+		}
+	}
+
+	return time.Time{}, false, nil
+}
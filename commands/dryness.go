@@ -15,3 +15,11 @@ var NO_PING_OPTION = tempest.CommandOption{
 	Description: "Do not ping the user associated with this ticket. Defaults to false (ping the user).",
 	Required:    false,
 }
+
+// Counterpart of NO_PING_OPTION for commands that do not ping the user by default
+var PING_OPTION = tempest.CommandOption{
+	Type:        tempest.BOOLEAN_OPTION_TYPE,
+	Name:        "ping",
+	Description: "Ping the user associated with this ticket. Defaults to false (don't ping the user).",
+	Required:    false,
+}
@@ -9,30 +9,33 @@
 package commands
 
 import (
-	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
 
 	"github.com/amatsagu/tempest"
 )
 
-var GetUserTicketCommand = tempest.Command{
-	Name:                "get-user-ticket",
-	Description:         "Get a link to the support ticket thread for a user, if it exists",
-	SlashCommandHandler: getUserTicketCommandImpl,
-	// By default, only let admins use the command.
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
-	Options: []tempest.CommandOption{{
-		Type:        tempest.USER_OPTION_TYPE,
-		Name:        "user",
-		Description: "User to get the ticket for",
-		Required:    true,
-	}},
+func (b *Bot) GetUserTicketCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "get-user-ticket",
+		Description:         "Get a link to the support ticket thread for a user, if it exists",
+		SlashCommandHandler: b.getUserTicketCommandImpl,
+		// By default, only let admins use the command.
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{{
+			Type:        tempest.USER_OPTION_TYPE,
+			Name:        "user",
+			Description: "User to get the ticket for",
+			Required:    true,
+		}},
+	}
 }
 
-func getUserTicketCommandImpl(itx *tempest.CommandInteraction) {
+func (b *Bot) getUserTicketCommandImpl(itx *tempest.CommandInteraction) {
 	userIDStr, present := itx.GetOptionValue("user")
 	if !present {
 		itx.SendLinearReply("You must specify a user", true)
@@ -45,13 +48,69 @@ func getUserTicketCommandImpl(itx *tempest.CommandInteraction) {
 		return
 	}
 
-	tid, err := db.Get().GetUserThread(userID)
-	if err == sql.ErrNoRows {
-		itx.SendLinearReply("This user does not have an open support ticket", true)
+	tickets, err := b.Store.GetUserTickets(userID)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to fetch user tickets", "error", err)
+		itx.SendLinearReply("Something went wrong while looking up this user's tickets", true)
 		return
 	}
 
 	itx.SendReply(tempest.ResponseMessageData{
-		Content: fmt.Sprintf("Support ticket thread: <#%d>", tid),
+		Content: buildTicketHistoryMessage(tickets),
 	}, true, nil)
 }
+
+// Maximum number of closed tickets to list in the reply
+const maxTicketHistoryEntries = 10
+
+// Build the reply listing the user's open ticket (if any) and their most recent closed tickets.
+// `tickets` is expected to be ordered newest first.
+func buildTicketHistoryMessage(tickets []db.Ticket) string {
+	var sb strings.Builder
+	var history []db.Ticket
+
+	open := false
+	for _, t := range tickets {
+		if t.Status == db.TICKET_OPEN {
+			// Users may have a ticket open in each category
+			if open {
+				sb.WriteString("\n")
+			}
+			open = true
+			fmt.Fprintf(&sb, "Support ticket thread: <#%d> (%s ticket #%d, opened <t:%d:R>)", t.ThreadID, t.Category, t.Number, t.OpenedAt.Unix())
+			if t.Assignee != 0 {
+				fmt.Fprintf(&sb, "\nAssigned to: <@%d>", t.Assignee)
+			} else {
+				sb.WriteString("\nAssigned to: nobody yet")
+			}
+		} else {
+			history = append(history, t)
+		}
+	}
+
+	if !open {
+		sb.WriteString("This user does not have an open support ticket")
+	}
+
+	if len(history) == 0 {
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "\n### Previous tickets (%d)", len(history))
+	for i, t := range history {
+		if i == maxTicketHistoryEntries {
+			fmt.Fprintf(&sb, "\n-# ...and %d more", len(history)-i)
+			break
+		}
+
+		fmt.Fprintf(&sb, "\n- #%d (%s): opened <t:%d:f>", t.Number, t.Category, t.OpenedAt.Unix())
+		if t.ClosedAt.Valid {
+			fmt.Fprintf(&sb, ", closed <t:%d:f>", t.ClosedAt.Time.Unix())
+		}
+		if t.ClosedBy != 0 {
+			fmt.Fprintf(&sb, " by <@%d>", t.ClosedBy)
+		}
+	}
+
+	return sb.String()
+}
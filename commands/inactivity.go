@@ -0,0 +1,187 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/types"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+// How often open tickets are checked for inactivity
+const inactivityCheckInterval = time.Hour
+
+// Name of the inactivity check's lock, taken so that bots sharing the database don't remind users twice
+const inactivityJob = "inactivity"
+
+// RunInactivityScheduler periodically reminds users who haven't replied to a helper in inactivity.reminder_days,
+// and closes their ticket if they still haven't replied inactivity.close_days after the reminder.
+// Blocks until `ctx` is cancelled.
+func (b *Bot) RunInactivityScheduler(ctx context.Context, client *tempest.BaseClient) {
+	if b.Config.Inactivity.ReminderAfter() == 0 {
+		slog.Info("inactivity reminders are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(inactivityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.runInactivityCheck(ctx, client, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check the open tickets for inactivity, unless another bot sharing the database is already checking them.
+// A panic only ends the current check, so the scheduler keeps running.
+func (b *Bot) runInactivityCheck(ctx context.Context, client *tempest.BaseClient, now time.Time) {
+	defer b.recoverJob(client, inactivityJob)
+
+	unlock, ok, err := b.Store.TryLockJob(ctx, inactivityJob)
+	if err != nil {
+		slog.Error("failed to lock the inactivity check", "error", err)
+		b.alert(client, alerts.Alert{
+			Kind:    alerts.KIND_DATABASE,
+			Summary: "Failed to lock the inactivity check",
+			Err:     err,
+		})
+		return
+	} else if !ok {
+		slog.Debug("skipping inactivity check, as another bot is running it")
+		return
+	}
+	defer unlock()
+
+	b.checkInactiveTickets(ctx, client, now)
+}
+
+// Check every open ticket for inactivity, stopping early if `ctx` is cancelled
+func (b *Bot) checkInactiveTickets(ctx context.Context, client *tempest.BaseClient, now time.Time) {
+	tickets, err := b.Store.GetOpenTickets()
+	if err != nil {
+		slog.Error("failed to load open tickets for inactivity check", "error", err)
+		b.alert(client, alerts.Alert{
+			Kind:    alerts.KIND_DATABASE,
+			Summary: "Failed to load the open tickets to check them for inactivity",
+			Err:     err,
+		})
+		return
+	}
+
+	for _, ticket := range tickets {
+		if ctx.Err() != nil {
+			return
+		}
+		b.checkInactiveTicket(client, ticket, now)
+	}
+}
+
+// Remind the user of a ticket, or close it, depending on how long it has been waiting on them
+func (b *Bot) checkInactiveTicket(client *tempest.BaseClient, ticket db.Ticket, now time.Time) {
+	logger := slog.With(slog.String("job", "inactivity"), logging.Ticket(ticket.Number))
+
+	channel, err := utils.GetChannelFromID(client, ticket.ThreadID)
+	if err != nil || !b.isTicketChannel(channel) || channel.ThreadMetadata == nil ||
+		channel.ThreadMetadata.Locked || channel.LastMessageID == nil {
+		return
+	}
+	lastMessageID := *channel.LastMessageID
+
+	reminder, err := b.Store.GetLastTicketEvent(ticket.Number, db.EVENT_INACTIVITY_REMINDER)
+	if err != nil && err != sql.ErrNoRows {
+		logger.Error("failed to fetch last inactivity reminder", "error", err)
+		b.alert(client, alerts.Alert{
+			Kind:    alerts.KIND_DATABASE,
+			Summary: "Failed to check a ticket for inactivity",
+			Err:     err,
+			Ticket:  ticket.Number,
+		})
+		return
+	}
+
+	// If the reminder is still the last message in the thread, nobody has replied since
+	if err == nil && reminder.MessageID == lastMessageID {
+		closeAfter := b.Config.Inactivity.CloseAfter()
+		if closeAfter == 0 || now.Sub(reminder.CreatedAt) < closeAfter {
+			return
+		}
+
+		b.autoCloseTicket(logger, client, channel, ticket)
+		return
+	}
+
+	if now.Sub(lastMessageID.CreationTimestamp()) < b.Config.Inactivity.ReminderAfter() {
+		return
+	}
+
+	// Only remind the user if the ticket is waiting on them, i.e. the last message is from a helper (or the bot)
+	msg, err := utils.GetChannelMessage(client, ticket.ThreadID, lastMessageID)
+	if err != nil || msg.Author == nil || msg.Author.ID == ticket.UserID {
+		return
+	}
+
+	b.sendInactivityReminder(logger, client, ticket, now)
+}
+
+// Ping the user of a ticket asking them to reply, and log the reminder
+func (b *Bot) sendInactivityReminder(logger *slog.Logger, client *tempest.BaseClient, ticket db.Ticket, now time.Time) {
+	content := i18n.Message(ticket.Locale, i18n.INACTIVITY_REMINDER, ticket.UserID)
+	if closeAfter := b.Config.Inactivity.CloseAfter(); closeAfter != 0 {
+		content += "\n" + i18n.Message(ticket.Locale, i18n.INACTIVITY_CLOSE_WARNING, now.Add(closeAfter).Unix())
+	}
+
+	msg, err := utils.SendDiscordMessage(client, ticket.ThreadID, types.CreateMessageParams{
+		Content:         content,
+		AllowedMentions: &tempest.AllowedMentions{Users: []tempest.Snowflake{ticket.UserID}},
+	}, nil, false)
+	if err != nil {
+		logger.Error("failed to send inactivity reminder", "error", err)
+		return
+	}
+
+	err = b.Store.LogTicketEvent(ticket.Number, db.EVENT_INACTIVITY_REMINDER, msg.ID, "")
+	if err != nil {
+		logger.Error("failed to log inactivity reminder", "error", err)
+		return
+	}
+	logger.Info("reminded user of inactive ticket")
+}
+
+// Close a ticket whose user never replied to the reminder, and log the outcome
+func (b *Bot) autoCloseTicket(logger *slog.Logger, client *tempest.BaseClient, channel types.Channel, ticket db.Ticket) {
+	kind, details := db.EVENT_AUTO_CLOSED, ""
+
+	err := b.closeTicket(logger, client, channel, ticket, 0)
+	if err != nil {
+		logger.Error("failed to close inactive ticket", "error", err)
+		b.alert(client, closeTicketAlert(ticket, err))
+		kind, details = db.EVENT_AUTO_CLOSE_FAILED, err.Error()
+	} else {
+		logger.Info("closed inactive ticket")
+	}
+
+	err = b.Store.LogTicketEvent(ticket.Number, kind, 0, details)
+	if err != nil {
+		logger.Error("failed to log automatic close", "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+)
+
+// A store whose open tickets can't be listed without panicking
+type panickingStore struct{ db.Store }
+
+func (panickingStore) GetOpenTickets() ([]db.Ticket, error) {
+	panic("boom")
+}
+
+// A panic in an inactivity check is reported, and only ends that check so the scheduler keeps running
+func TestInactivityCheckRecoversPanics(t *testing.T) {
+	b := newTestBot(t, nil)
+	b.Store = panickingStore{b.store}
+
+	b.runInactivityCheck(t.Context(), &b.client.BaseClient, time.Now())
+
+	alerted := b.srv.Wait(testTimeout, func() bool {
+		return len(b.srv.Messages(testTroubleshootingID)) > 0
+	})
+	if !alerted {
+		t.Error("the panic was not reported")
+	}
+}
+
+// A ticket waiting on its user gets a reminder after inactivity.reminder_days, and is closed if the user still hasn't
+// replied inactivity.close_days later
+func TestInactivityCheckRemindsThenCloses(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+	ticket, err := b.store.GetThreadTicket(thread.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &b.client.BaseClient
+	reminderAfter, closeAfter := b.Config.Inactivity.ReminderAfter(), b.Config.Inactivity.CloseAfter()
+
+	// Too early for a reminder
+	b.runInactivityCheck(t.Context(), client, time.Now().Add(reminderAfter-time.Hour))
+	if _, err := b.store.GetLastTicketEvent(ticket.Number, db.EVENT_INACTIVITY_REMINDER); err == nil {
+		t.Fatal("the user was reminded before inactivity.reminder_days")
+	}
+
+	b.runInactivityCheck(t.Context(), client, time.Now().Add(reminderAfter+time.Hour))
+	messages := b.srv.Messages(thread.ID)
+	reminder := messages[len(messages)-1]
+	want := i18n.Message(ticket.Locale, i18n.INACTIVITY_REMINDER, testUser.ID)
+	if !strings.HasPrefix(reminder.Content, want) {
+		t.Fatalf("the last message of the ticket is %q, want the reminder", reminder.Content)
+	}
+
+	// Still within inactivity.close_days of the reminder
+	b.runInactivityCheck(t.Context(), client, time.Now().Add(closeAfter-time.Hour))
+	if ticket, _ := b.store.GetThreadTicket(thread.ID); ticket.Status != db.TICKET_OPEN {
+		t.Fatalf("the ticket was closed before inactivity.close_days, its status is %q", ticket.Status)
+	}
+
+	b.runInactivityCheck(t.Context(), client, time.Now().Add(closeAfter+time.Hour))
+	if _, err := b.store.GetLastTicketEvent(ticket.Number, db.EVENT_AUTO_CLOSED); err != nil {
+		t.Errorf("the automatic close was not logged: %v", err)
+	}
+	if ticket, _ := b.store.GetThreadTicket(thread.ID); ticket.Status == db.TICKET_OPEN {
+		t.Error("the inactive ticket was not closed")
+	}
+}
+
+// A store whose jobs are always locked by another bot
+type lockedStore struct{ db.Store }
+
+func (lockedStore) TryLockJob(ctx context.Context, job string) (func(), bool, error) {
+	return nil, false, nil
+}
+
+// The inactivity check is skipped while another bot sharing the database runs it
+func TestInactivityCheckSkipsWhenLocked(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+	before := len(b.srv.Messages(thread.ID))
+	b.Store = lockedStore{b.store}
+
+	b.runInactivityCheck(t.Context(), &b.client.BaseClient, time.Now().Add(b.Config.Inactivity.ReminderAfter()+time.Hour))
+	if after := len(b.srv.Messages(thread.ID)); after != before {
+		t.Errorf("%d messages were sent while another bot held the lock", after-before)
+	}
+}
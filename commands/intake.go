@@ -0,0 +1,206 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/responses"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Custom ID of the Open Ticket button on panels sent before ticket categories existed, which opens password tickets
+const LegacyOpenTicketButtonID = "open-ticket-button"
+
+// Return the custom ID of the Open Ticket button of a ticket category
+func OpenTicketButtonID(category string) string {
+	return LegacyOpenTicketButtonID + ":" + category
+}
+
+// Return the custom ID of the intake form shown when the Open Ticket button of a ticket category is pressed
+func OpenTicketModalID(category string) string {
+	return "open-ticket-modal:" + category
+}
+
+// Return the ticket category of an Open Ticket button or intake form from its custom ID
+func categoryFromCustomID(customID string) string {
+	_, category, found := strings.Cut(customID, ":")
+	if !found {
+		return config.PASSWORD_CATEGORY
+	}
+
+	return category
+}
+
+// A question of the intake form, which answers what helpers would otherwise ask every user first
+type intakeQuestion struct {
+	ID          string // Custom ID of the text input
+	Summary     string // What the answer is labelled with in the summary posted for helpers, and in the database
+	Label       string // The question, in the user's locale
+	Description string // An example of what to answer, in the user's locale
+	Required    bool
+	Paragraph   bool
+	MaxLength   uint16
+}
+
+// A question of the built-in intake form of password tickets, translated to the user's locale
+type translatedQuestion struct {
+	ID          string
+	Summary     string
+	Label       i18n.MessageID
+	Description i18n.MessageID
+	Required    bool
+	MaxLength   uint16
+}
+
+var passwordIntakeQuestions = []translatedQuestion{
+	{
+		ID:          "intake-username",
+		Summary:     "Username",
+		Label:       i18n.INTAKE_USERNAME,
+		Description: i18n.INTAKE_USERNAME_HINT,
+		Required:    true,
+		MaxLength:   64,
+	},
+	{
+		ID:          "intake-account-created",
+		Summary:     "Account created",
+		Label:       i18n.INTAKE_ACCOUNT_CREATED,
+		Description: i18n.INTAKE_ACCOUNT_CREATED_HINT,
+		MaxLength:   100,
+	},
+	{
+		ID:          "intake-last-played",
+		Summary:     "Last played",
+		Label:       i18n.INTAKE_LAST_PLAYED,
+		Description: i18n.INTAKE_LAST_PLAYED_HINT,
+		MaxLength:   100,
+	},
+	{
+		ID:          "intake-platform",
+		Summary:     "Platform and browser",
+		Label:       i18n.INTAKE_PLATFORM,
+		Description: i18n.INTAKE_PLATFORM_HINT,
+		Required:    true,
+		MaxLength:   100,
+	},
+}
+
+// Return the questions of the intake form of the category, in the user's locale.
+// The password category asks the built-in questions unless others are configured; other categories without
+// configured questions have no form.
+func intakeQuestions(category config.Category, locale tempest.Language) []intakeQuestion {
+	if category.ID == config.PASSWORD_CATEGORY && len(category.Questions) == 0 {
+		questions := make([]intakeQuestion, 0, len(passwordIntakeQuestions))
+		for _, q := range passwordIntakeQuestions {
+			questions = append(questions, intakeQuestion{
+				ID:          q.ID,
+				Summary:     q.Summary,
+				Label:       i18n.Message(locale, q.Label),
+				Description: i18n.Message(locale, q.Description),
+				Required:    q.Required,
+				MaxLength:   q.MaxLength,
+			})
+		}
+		return questions
+	}
+
+	questions := make([]intakeQuestion, 0, len(category.Questions))
+	for _, q := range category.Questions {
+		questions = append(questions, intakeQuestion{
+			ID:          q.ID,
+			Summary:     q.Label,
+			Label:       q.Label,
+			Description: q.Description,
+			Required:    q.Required,
+			Paragraph:   q.Paragraph,
+			MaxLength:   q.MaxLength,
+		})
+	}
+
+	return questions
+}
+
+// Build the intake form of the category, in the user's locale
+func intakeModal(category config.Category, questions []intakeQuestion, locale tempest.Language) tempest.ResponseModalData {
+	// Can have at most 5 components.
+	components := make([]tempest.LayoutComponent, 0, len(questions))
+	for _, q := range questions {
+		style := tempest.SHORT_TEXT_INPUT_STYLE
+		if q.Paragraph {
+			style = tempest.PARAGRAPH_TEXT_INPUT_STYLE
+		}
+
+		components = append(components, tempest.LabelComponent{
+			Type:        tempest.LABEL_COMPONENT_TYPE,
+			Label:       q.Label,
+			Description: q.Description,
+			Component: tempest.TextInputComponent{
+				Type:      tempest.TEXT_INPUT_COMPONENT_TYPE,
+				CustomID:  q.ID,
+				Style:     style,
+				Required:  q.Required,
+				MaxLength: q.MaxLength,
+			},
+		})
+	}
+
+	title := i18n.Message(locale, i18n.INTAKE_TITLE)
+	if category.ID != config.PASSWORD_CATEGORY {
+		title = category.Name
+		if runes := []rune(title); len(runes) > maxModalTitleLength {
+			title = string(runes[:maxModalTitleLength])
+		}
+	}
+
+	return tempest.ResponseModalData{
+		CustomID:   OpenTicketModalID(category.ID),
+		Title:      title,
+		Components: components,
+	}
+}
+
+// Return the answers given in the intake form, in the order the questions are asked
+func intakeAnswers(itx tempest.ModalInteraction, questions []intakeQuestion) []db.IntakeAnswer {
+	values := map[string]string{}
+	for _, component := range itx.Data.Components {
+		label, ok := component.(tempest.LabelComponent)
+		if !ok {
+			continue
+		}
+		if input, ok := label.Component.(tempest.TextInputComponent); ok {
+			values[input.CustomID] = strings.TrimSpace(input.Value)
+		}
+	}
+
+	answers := make([]db.IntakeAnswer, 0, len(questions))
+	for _, q := range questions {
+		answers = append(answers, db.IntakeAnswer{Question: q.Summary, Answer: values[q.ID]})
+	}
+
+	return answers
+}
+
+// Build the summary of the intake form answers posted in the ticket thread for helpers
+func intakeSummary(answers []db.IntakeAnswer) string {
+	var sb strings.Builder
+	sb.WriteString("### Ticket details")
+	for _, a := range answers {
+		answer := "_Not answered_"
+		if a.Answer != "" {
+			answer = responses.EscapeMarkdown(a.Answer)
+		}
+		sb.WriteString("\n**" + a.Question + ":** " + answer)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,251 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/metrics"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Sent in reply to interactions received after the bot started shutting down
+const SHUTTING_DOWN_MESSAGE = "The bot is restarting, please try again in a minute."
+
+// Sent in reply to interactions whose handler panicked before answering
+const HANDLER_PANIC_MESSAGE = "Something went wrong on my end. The developers have been notified, please try again later."
+
+// tasks counts the interaction handlers and background jobs running, so that shutting down can wait for them.
+// tempest runs every handler in its own goroutine, which keeps running after the initial response is sent.
+type tasks struct {
+	mu       sync.Mutex
+	running  int
+	stopping bool
+	idle     chan struct{} // Closed once stopping and nothing is running anymore
+}
+
+// Record that a task started. Returns false if the bot is shutting down and the task must not run.
+// Background jobs started by a running task are still accepted while shutting down, as the task is waiting for them.
+func (t *tasks) start(background bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopping && (!background || t.running == 0) {
+		return false
+	}
+
+	t.running++
+	return true
+}
+
+// Record that a task started with start finished
+func (t *tasks) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running--
+	if t.stopping && t.running == 0 {
+		close(t.idle)
+	}
+}
+
+// Refuse new tasks, and wait for the running ones until `ctx` is done
+func (t *tasks) stop(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.stopping {
+		t.stopping = true
+		t.idle = make(chan struct{})
+		if t.running == 0 {
+			close(t.idle)
+		}
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return fmt.Errorf("%d interaction handlers or background jobs still running: %w", t.running, ctx.Err())
+	}
+}
+
+// Run an interaction handler, so that Shutdown waits for it, it is counted in the metrics, and its log lines
+// identify the interaction (see logging.For). A panic in the handler is recovered and reported.
+// `kind` is one of the metrics.INTERACTION_ constants, and `name` the command or custom ID.
+// `reply` (if not nil) answers the interaction with an ephemeral message: SHUTTING_DOWN_MESSAGE while shutting down,
+// or HANDLER_PANIC_MESSAGE if the handler panicked.
+func (b *Bot) handle(itx *tempest.Interaction, kind string, name string, reply func(content string), handler func()) {
+	if !b.tasks.start(false) {
+		if reply != nil {
+			reply(SHUTTING_DOWN_MESSAGE)
+		}
+		return
+	}
+	defer b.tasks.done()
+
+	done := logging.Start(itx, kind, name)
+	defer done()
+
+	metrics.Interactions.WithLabelValues(kind, name).Inc()
+	start := time.Now()
+	defer func() {
+		elapsed := time.Since(start)
+		metrics.HandlerDuration.WithLabelValues(kind, name).Observe(elapsed.Seconds())
+		logging.For(itx).Debug("handled interaction", "duration", elapsed)
+	}()
+
+	// Runs first, while the interaction is still tracked and its logger registered
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		stack := string(debug.Stack())
+		logging.For(itx).Error("interaction handler panicked", "panic", fmt.Sprint(recovered), "stack", stack)
+		metrics.Panics.WithLabelValues(kind, name).Inc()
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_PANIC,
+			Summary:     fmt.Sprintf("The %s %q crashed", kind, name),
+			Err:         fmt.Errorf("panic: %v", recovered),
+			Details:     stack,
+			Interaction: itx,
+		})
+
+		// Does nothing if the handler already answered the interaction, as only one initial response can be sent
+		if reply != nil {
+			reply(HANDLER_PANIC_MESSAGE)
+		}
+	}()
+
+	handler()
+}
+
+// Command wraps the handlers of a command (or subcommand) so that they only run for members with the role it needs
+// (see commandRole), shutting down waits for them, they are counted in the metrics, and their panics are recovered
+// (see handle). Members without the role get no autocomplete choices. Register every command through it.
+func (b *Bot) Command(cmd tempest.Command) tempest.Command {
+	handler := cmd.SlashCommandHandler
+	if handler == nil {
+		// Command groups only hold subcommands
+		return cmd
+	}
+
+	if autoComplete := cmd.AutoCompleteHandler; autoComplete != nil {
+		cmd.AutoCompleteHandler = func(itx tempest.CommandInteraction) []tempest.CommandOptionChoice {
+			name := strings.ReplaceAll(itx.Data.Name, "@", " ")
+			choices := []tempest.CommandOptionChoice{}
+			b.handle(itx.Interaction, metrics.INTERACTION_AUTOCOMPLETE, name, nil, func() {
+				if _, ok := b.allowed(itx.Interaction, name); ok {
+					choices = autoComplete(itx)
+				}
+			})
+
+			return choices
+		}
+	}
+
+	cmd.SlashCommandHandler = func(itx *tempest.CommandInteraction) {
+		// tempest names subcommands "group@subcommand"
+		name := strings.ReplaceAll(itx.Data.Name, "@", " ")
+		b.handle(itx.Interaction, metrics.INTERACTION_COMMAND, name, func(content string) {
+			itx.SendLinearReply(content, true)
+		}, func() {
+			if b.authorize(itx, name) {
+				handler(itx)
+			}
+		})
+	}
+
+	return cmd
+}
+
+// Component wraps the handler of a static component like Command does. Components check who may use them themselves.
+func (b *Bot) Component(handler func(tempest.ComponentInteraction)) func(tempest.ComponentInteraction) {
+	return func(itx tempest.ComponentInteraction) {
+		b.handle(itx.Interaction, metrics.INTERACTION_COMPONENT, itx.Data.CustomID, func(content string) {
+			itx.AcknowledgeWithLinearMessage(content, true)
+		}, func() {
+			handler(itx)
+		})
+	}
+}
+
+// Modal wraps the handler of a modal like Component does
+func (b *Bot) Modal(handler func(tempest.ModalInteraction)) func(tempest.ModalInteraction) {
+	return func(itx tempest.ModalInteraction) {
+		b.handle(itx.Interaction, metrics.INTERACTION_MODAL, itx.Data.CustomID, func(content string) {
+			itx.AcknowledgeWithLinearMessage(content, true)
+		}, func() {
+			handler(itx)
+		})
+	}
+}
+
+// Go runs `job` in a new goroutine, which Shutdown waits for.
+// Use it for work that outlives the handler that started it, such as sending a follow-up message.
+// A panic in `job` is recovered and reported like the panics of handlers (see recoverJob), as it would otherwise
+// stop the bot. `name` identifies the job in the logs, metrics and alerts, and `client` is used to post the alert.
+func (b *Bot) Go(client *tempest.BaseClient, name string, job func()) {
+	if !b.tasks.start(true) {
+		slog.Warn("not starting a background job, the bot is shutting down", "job", name)
+		return
+	}
+
+	go func() {
+		defer b.tasks.done()
+		defer b.recoverJob(client, name)
+
+		job()
+	}()
+}
+
+// Name of the job posting alerts, see alert
+const alertJob = "alert"
+
+// Recover from a panic in a background job, and report it. Must be deferred.
+// Long-running jobs defer it around each unit of work, so that one panic does not stop them for good.
+func (b *Bot) recoverJob(client *tempest.BaseClient, name string) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	stack := string(debug.Stack())
+	slog.Error("background job panicked", "job", name, "panic", fmt.Sprint(recovered), "stack", stack)
+	metrics.Panics.WithLabelValues(metrics.JOB, name).Inc()
+
+	// Reporting a panic in posting an alert would likely panic again
+	if name == alertJob {
+		return
+	}
+	b.alert(client, alerts.Alert{
+		Kind:    alerts.KIND_PANIC,
+		Summary: fmt.Sprintf("The background job %q crashed", name),
+		Err:     fmt.Errorf("panic: %v", recovered),
+		Details: stack,
+	})
+}
+
+// Shutdown refuses new interactions, and waits for the running handlers and background jobs until `ctx` is done.
+// Stop receiving interactions first, so that the interactions already received are handled.
+func (b *Bot) Shutdown(ctx context.Context) error {
+	return b.tasks.stop(ctx)
+}
@@ -0,0 +1,33 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// A panic in a background job is posted to the troubleshooting channel, like those of handlers
+func TestGoReportsPanics(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	b.Go(&b.client.BaseClient, "exploding", func() { panic("boom") })
+
+	alerted := b.srv.Wait(testTimeout, func() bool {
+		return len(b.srv.Messages(testTroubleshootingID)) > 0
+	})
+	if !alerted {
+		t.Fatal("the panic was not reported")
+	}
+
+	raw, _ := json.Marshal(b.srv.Messages(testTroubleshootingID)[0])
+	if !strings.Contains(string(raw), `\"exploding\" crashed`) || !strings.Contains(string(raw), "boom") {
+		t.Errorf("the panic was reported as %s", raw)
+	}
+}
@@ -11,13 +11,15 @@ import (
 	"context"
 	"errors"
 	"io"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/metrics"
+
 	"github.com/amatsagu/tempest"
 	"github.com/google/go-github/v74/github"
-	githubClient "github.com/pagefaultgames/ticketune/github-client"
 )
 
 // Register the command (add this to your command registration logic)
@@ -203,7 +205,7 @@ func getLabelComponent[T tempest.StringSelectComponent | tempest.TextInputCompon
 // Error indicating that the issue creation request timed out
 var ErrIssueTimeout = errors.New("issue creation timed out")
 
-func HandleNewIssueModal(mitx tempest.ModalInteraction) {
+func (b *Bot) HandleNewIssueModal(mitx tempest.ModalInteraction) {
 	// This means that the interaction was not in a guild, which should not be possible unless discord is broken
 	if mitx.Member == nil {
 		_ = mitx.AcknowledgeWithLinearMessage("Error: Unable to identify user", true)
@@ -238,13 +240,11 @@ func HandleNewIssueModal(mitx tempest.ModalInteraction) {
 	if additionalContext == "" {
 		steps = "_No response_"
 	}
-	err := mitx.Defer(true)
-	if err != nil {
-		log.Print("Failed to defer issue modal: ", err)
-	}
+	logger := logging.For(mitx.Interaction)
 
+	err := mitx.Defer(true)
 	if err != nil {
-		log.Print("Failed to send follow-up message: ", err)
+		logger.Error("failed to defer issue modal", "error", err)
 	}
 
 	var issueBody string
@@ -259,7 +259,9 @@ func HandleNewIssueModal(mitx tempest.ModalInteraction) {
 			"\n\n### Reproduction\n\n" + steps +
 			"\n\n### Additional context\n\n" + additionalContext
 
-	go func() {
+	// Creating the issue can take longer than the handler is given to respond, so it is done in the background.
+	// Shutting down waits for it, so that the user is always told whether the issue was created.
+	b.Go(mitx.Client, "new-issue", func() {
 		// We have 30 seconds to respond
 		ctx, cancel := context.WithTimeoutCause(context.Background(), issueTimeout, ErrIssueTimeout)
 		defer cancel()
@@ -268,10 +270,6 @@ func HandleNewIssueModal(mitx tempest.ModalInteraction) {
 				mitx.SendLinearFollowUp("Issue creation timed out. Please try again later.", true)
 			}
 		})
-		// context.
-		// TODO: replace with actual owner/org
-		owner := "pagefaultgames"
-		repo := "pokerogue"
 		issueRequest := &github.IssueRequest{
 			// Assuming discord respected our 200 character limit,
 			// this will be less than the 256 character github limit for titles
@@ -280,21 +278,39 @@ func HandleNewIssueModal(mitx tempest.ModalInteraction) {
 			Type:   github.Ptr("bug"),
 			Labels: &issueLabels,
 		}
-		issue, resp, err := githubClient.Client.Issues.Create(ctx, owner, repo, issueRequest)
+		issue, resp, err := b.GitHub.Issues.Create(ctx, b.Config.GitHub.Owner, b.Config.GitHub.Repo, issueRequest)
 		if err != nil {
+			if context.Cause(ctx) == ErrIssueTimeout {
+				metrics.GitHubIssues.WithLabelValues(metrics.ISSUE_TIMED_OUT).Inc()
+			} else if resp != nil && resp.Rate.Remaining == 0 {
+				metrics.GitHubIssues.WithLabelValues(metrics.ISSUE_RATE_LIMITED).Inc()
+			} else {
+				metrics.GitHubIssues.WithLabelValues(metrics.ISSUE_FAILED).Inc()
+			}
+
+			alert := alerts.Alert{
+				Kind:        alerts.KIND_GITHUB,
+				Summary:     "Failed to create a GitHub issue",
+				Err:         err,
+				Interaction: mitx.Interaction,
+			}
 			if resp != nil && resp.Rate.Remaining == 0 {
+				b.alert(mitx.Client, alert)
 				mitx.SendLinearFollowUp("GitHub rate limit exceeded. Please try again later.", true)
 				return
 			} else if resp != nil {
 				body, err := io.ReadAll(resp.Body)
 				if err != nil {
-					log.Printf("Failed to read GitHub API response body: %v", err)
+					logger.Error("failed to read GitHub API response body", "error", err)
 				}
-				log.Printf("GitHub API response body: %s", string(body))
+				logger.Error("failed to create GitHub issue", "status", resp.StatusCode, "body", string(body))
+				alert.Details = string(body)
 			}
+			b.alert(mitx.Client, alert)
 			mitx.SendLinearFollowUp("Failed to create issue: "+err.Error(), true)
 			return
 		}
+		metrics.GitHubIssues.WithLabelValues(metrics.ISSUE_CREATED).Inc()
 		mitx.SendLinearFollowUp("Issue created: "+issue.GetHTMLURL(), true)
-	}()
+	})
 }
@@ -11,6 +11,7 @@ package commands
 import (
 	"strconv"
 
+	"github.com/pagefaultgames/ticketune/responses"
 	"github.com/pagefaultgames/ticketune/utils"
 
 	"github.com/amatsagu/tempest"
@@ -25,59 +26,63 @@ var OldAccountCommandGroup = tempest.Command{
 	Description: oldAccountCommandDescription,
 }
 
-var OldAccountDefault = tempest.Command{
-	Name:                "default",
-	Description:         oldAccountDefaultDescription,
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	SlashCommandHandler: func(itx *tempest.CommandInteraction) { oldAccountCommandImpl(itx, true) },
-	Options: []tempest.CommandOption{
-		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "username",
-			Description: "The username of the old account",
-			Required:    false,
-			MinLength:   1,
-			MaxLength:   64,
+func (b *Bot) OldAccountDefault() tempest.Command {
+	return tempest.Command{
+		Name:                "default",
+		Description:         oldAccountDefaultDescription,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		SlashCommandHandler: func(itx *tempest.CommandInteraction) { b.oldAccountCommandImpl(itx, true) },
+		Options: []tempest.CommandOption{
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "username",
+				Description: "The username of the old account",
+				Required:    false,
+				MinLength:   1,
+				MaxLength:   64,
+			},
+			NO_PING_OPTION,
 		},
-		NO_PING_OPTION,
-	},
-	Contexts: []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Contexts: []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
 }
 
-var OldAccountSpecific = tempest.Command{
-	Name:                "specific",
-	Description:         oldAccountSpecificDescription,
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	SlashCommandHandler: func(itx *tempest.CommandInteraction) { oldAccountCommandImpl(itx, false) },
-	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
-	Options: []tempest.CommandOption{
-		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "username",
-			Description: "The username of the old account",
-			Required:    true,
-			MinLength:   1,
-			MaxLength:   64,
-		},
-		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "unit",
-			Description: "The unit of time",
-			Required:    true,
-			Choices: []tempest.CommandOptionChoice{
-				{Name: "week", Value: "week"},
-				{Name: "month", Value: "month"},
-				{Name: "year", Value: "year"},
+func (b *Bot) OldAccountSpecific() tempest.Command {
+	return tempest.Command{
+		Name:                "specific",
+		Description:         oldAccountSpecificDescription,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		SlashCommandHandler: func(itx *tempest.CommandInteraction) { b.oldAccountCommandImpl(itx, false) },
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "username",
+				Description: "The username of the old account",
+				Required:    true,
+				MinLength:   1,
+				MaxLength:   64,
 			},
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "unit",
+				Description: "The unit of time",
+				Required:    true,
+				Choices: []tempest.CommandOptionChoice{
+					{Name: "week", Value: "week"},
+					{Name: "month", Value: "month"},
+					{Name: "year", Value: "year"},
+				},
+			},
+			{
+				Type:        tempest.INTEGER_OPTION_TYPE,
+				Name:        "amount",
+				Description: "The number of time units. If omitted, will just say \"in a few [units]\"",
+				Required:    false,
+			},
+			NO_PING_OPTION,
 		},
-		{
-			Type:        tempest.INTEGER_OPTION_TYPE,
-			Name:        "amount",
-			Description: "The number of time units. If omitted, will just say \"in a few [units]\"",
-			Required:    false,
-		},
-		NO_PING_OPTION,
-	},
+	}
 }
 
 // Given an amount and a unit, return a string like "since last [unit]" or "for [amount] [units]"
@@ -93,26 +98,32 @@ func computeTimeString(amount int, unit string) string {
 	}
 }
 
+const oldAccountMessage = "The account {username} has not played {time_span}. " +
+	"It is likely that you are misremembering your username."
+
 // Build the message to send to the user
 // Notably, `username` is *not* the discord username, but the old account username
 func buildMessage(username string, amount int, unit string) string {
-	return "The account ``" + username + "`` has not played " + computeTimeString(amount, unit) + ". " +
-		"It is likely that you are misremembering your username."
+	return responses.Render(oldAccountMessage, responses.Vars{
+		"username":  responses.Code(username),
+		"time_span": responses.Text(computeTimeString(amount, unit)),
+	})
 }
 
-const defaultMessage string = " has not played for a long time." +
-	" It is likely that you are misremembering your username."
-
 func defaultMessageWithUsername(username string) string {
+	var account responses.Value = responses.Code(username)
 	// If username was empty, use the generic "The account you provided"
 	if username == "" {
-		return "The account you provided" + defaultMessage
+		account = responses.Raw("you provided")
 	}
 
-	return "The account ``" + username + "``" + defaultMessage
+	return responses.Render(oldAccountMessage, responses.Vars{
+		"username":  account,
+		"time_span": responses.Text("for a long time"),
+	})
 }
 
-func oldAccountCommandImpl(itx *tempest.CommandInteraction, isDefault bool) {
+func (b *Bot) oldAccountCommandImpl(itx *tempest.CommandInteraction, isDefault bool) {
 	// if no arguments, then use a default message
 	var msg string
 	if !isDefault {
@@ -135,5 +146,5 @@ func oldAccountCommandImpl(itx *tempest.CommandInteraction, isDefault bool) {
 		msg = defaultMessageWithUsername(username)
 	}
 
-	utils.SayCommandTemplate(itx, msg, "The user has been notified.")
+	b.say(itx, msg, "The user has been notified.", utils.SayOptions{})
 }
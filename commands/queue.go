@@ -0,0 +1,276 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+func (b *Bot) QueueCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "queue",
+		Description:         "List the open support tickets",
+		SlashCommandHandler: b.queueCommandImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "sort",
+			Description: "How to order the tickets. Defaults to oldest first",
+			Required:    false,
+			Choices: []tempest.CommandOptionChoice{
+				{Name: "Oldest first", Value: string(queueSortOldest)},
+				{Name: "Unanswered first", Value: string(queueSortUnanswered)},
+			},
+		}},
+	}
+}
+
+type queueSort string
+
+const (
+	queueSortOldest     queueSort = "oldest"     // By when the ticket was opened
+	queueSortUnanswered queueSort = "unanswered" // Tickets whose last message is from the user first, longest waiting first
+)
+
+// Prefix of the custom IDs of the queue's buttons, which look like "queue:<sort>:<page>:<button>"
+const queueComponentPrefix = "queue"
+
+// Number of tickets shown per page
+const queuePageSize = 8
+
+// Number of threads fetched from Discord at the same time
+const queueFetchWorkers = 4
+
+// A ticket, along with the state of its thread
+type queueEntry struct {
+	ticket         db.Ticket
+	lastActivity   time.Time // When the last message was sent in the thread
+	awaitingHelper bool      // Whether the last message in the thread was sent by the ticket's user
+}
+
+// Fetch the thread of a ticket from Discord. Returns false if the thread no longer exists or was locked
+func loadQueueEntry(client *tempest.BaseClient, ticket db.Ticket) (queueEntry, bool) {
+	channel, err := utils.GetChannelFromID(client, ticket.ThreadID)
+	if err != nil || channel.ThreadMetadata == nil || channel.ThreadMetadata.Locked {
+		return queueEntry{}, false
+	}
+
+	entry := queueEntry{ticket: ticket, lastActivity: ticket.OpenedAt}
+	if channel.LastMessageID == nil {
+		return entry, true
+	}
+
+	// Snowflakes encode their creation time, so the last activity does not require fetching the message
+	entry.lastActivity = channel.LastMessageID.CreationTimestamp()
+
+	// The last message may have been deleted, in which case we can't tell who sent it
+	msg, err := utils.GetChannelMessage(client, ticket.ThreadID, *channel.LastMessageID)
+	if err == nil && msg.Author != nil {
+		entry.awaitingHelper = msg.Author.ID == ticket.UserID
+	}
+
+	return entry, true
+}
+
+// Load every open ticket whose thread still exists, sorted according to `sortBy`
+func (b *Bot) loadQueue(client *tempest.BaseClient, sortBy queueSort) ([]queueEntry, error) {
+	tickets, err := b.Store.GetOpenTickets()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*queueEntry, len(tickets))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range queueFetchWorkers {
+		wg.Go(func() {
+			for i := range indices {
+				entry, ok := loadQueueEntry(client, tickets[i])
+				if ok {
+					results[i] = &entry
+				}
+			}
+		})
+	}
+	for i := range tickets {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	entries := make([]queueEntry, 0, len(tickets))
+	for _, entry := range results {
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+
+	// Tickets come out of the database oldest first
+	if sortBy == queueSortUnanswered {
+		slices.SortStableFunc(entries, func(a, b queueEntry) int {
+			if a.awaitingHelper != b.awaitingHelper {
+				if a.awaitingHelper {
+					return -1
+				}
+				return 1
+			}
+			return a.lastActivity.Compare(b.lastActivity)
+		})
+	}
+
+	return entries, nil
+}
+
+// Build a button for the queue message
+func queueButton(label string, sortBy queueSort, page int, name string, disabled bool) tempest.ButtonComponent {
+	return tempest.ButtonComponent{
+		Type:     tempest.BUTTON_COMPONENT_TYPE,
+		CustomID: fmt.Sprintf("%s:%s:%d:%s", queueComponentPrefix, sortBy, page, name),
+		Label:    label,
+		Style:    tempest.SECONDARY_BUTTON_STYLE,
+		Disabled: disabled,
+	}
+}
+
+// Describe a single ticket of the queue
+func formatQueueEntry(entry queueEntry) string {
+	t := entry.ticket
+
+	waitingOn := "user"
+	if entry.awaitingHelper {
+		waitingOn = "**helper**"
+	}
+
+	assignee := "unassigned"
+	if t.Assignee != 0 {
+		assignee = fmt.Sprintf("<@%d>", t.Assignee)
+	}
+
+	return fmt.Sprintf(
+		"**#%d** <#%d> — <@%d>\n-# Opened <t:%d:R> · Last activity <t:%d:R> · Waiting on %s · %s",
+		t.Number, t.ThreadID, t.UserID, t.OpenedAt.Unix(), entry.lastActivity.Unix(), waitingOn, assignee,
+	)
+}
+
+// Build the queue message showing `page` of `entries`
+func buildQueueMessage(entries []queueEntry, sortBy queueSort, page int) tempest.ResponseMessageData {
+	pageCount := max((len(entries)+queuePageSize-1)/queuePageSize, 1)
+	page = min(max(page, 0), pageCount-1)
+
+	sortName := "oldest first"
+	if sortBy == queueSortUnanswered {
+		sortName = "unanswered first"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Ticket queue\n%d open tickets, %s. Page %d/%d", len(entries), sortName, page+1, pageCount)
+
+	components := []tempest.AnyComponent{
+		tempest.TextDisplayComponent{Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE, Content: sb.String()},
+		tempest.SeparatorComponent{Type: tempest.SEPARATOR_COMPONENT_TYPE, Divider: true},
+	}
+
+	start := page * queuePageSize
+	end := min(start+queuePageSize, len(entries))
+	lines := make([]string, 0, end-start)
+	for _, entry := range entries[start:end] {
+		lines = append(lines, formatQueueEntry(entry))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "There are no open tickets!")
+	}
+
+	components = append(components,
+		tempest.TextDisplayComponent{Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE, Content: strings.Join(lines, "\n")},
+		tempest.ActionRowComponent{
+			Type: tempest.ACTION_ROW_COMPONENT_TYPE,
+			Components: []tempest.InteractiveComponent{
+				queueButton("Previous", sortBy, page-1, "prev", page == 0),
+				queueButton("Next", sortBy, page+1, "next", page >= pageCount-1),
+				queueButton("Refresh", sortBy, page, "refresh", false),
+				queueButton("Oldest first", queueSortOldest, 0, "sort", sortBy == queueSortOldest),
+				queueButton("Unanswered first", queueSortUnanswered, 0, "sort", sortBy == queueSortUnanswered),
+			},
+		},
+	)
+
+	return tempest.ResponseMessageData{
+		Flags: tempest.IS_COMPONENTS_V2_MESSAGE_FLAG,
+		Components: []tempest.LayoutComponent{
+			tempest.ContainerComponent{
+				Type:       tempest.CONTAINER_COMPONENT_TYPE,
+				Components: components,
+			},
+		},
+		// Listing tickets should never ping anyone
+		AllowedMentions: &tempest.AllowedMentions{},
+	}
+}
+
+func (b *Bot) queueCommandImpl(itx *tempest.CommandInteraction) {
+	sortBy := queueSortOldest
+	sortOption, _ := utils.GetOption[string](itx, "sort", false)
+	if sortOption == string(queueSortUnanswered) {
+		sortBy = queueSortUnanswered
+	}
+
+	logger := logging.For(itx.Interaction)
+
+	// Fetching every thread can take longer than Discord's 3 second limit
+	err := itx.Defer(true)
+	if err != nil {
+		logger.Error("failed to defer queue command", "error", err)
+	}
+
+	entries, err := b.loadQueue(itx.Client, sortBy)
+	if err != nil {
+		logger.Error("failed to load ticket queue", "error", err)
+		itx.SendLinearReply("Something went wrong while loading the open tickets from my database.", true)
+		return
+	}
+
+	itx.SendReply(buildQueueMessage(entries, sortBy, 0), true, nil)
+}
+
+// Handle the queue's pagination and sorting buttons. `args` is [sort, page, button name]
+func (b *Bot) queueComponentHandler(itx *tempest.ComponentInteraction, args []string) {
+	logger := logging.For(itx.Interaction)
+	if len(args) < 2 {
+		logger.Warn("received malformed queue component custom ID", "custom_id", itx.Data.CustomID)
+		return
+	}
+
+	sortBy := queueSort(args[0])
+	if sortBy != queueSortUnanswered {
+		sortBy = queueSortOldest
+	}
+	page, _ := strconv.Atoi(args[1])
+
+	entries, err := b.loadQueue(itx.Client, sortBy)
+	if err != nil {
+		logger.Error("failed to load ticket queue", "error", err)
+		return
+	}
+
+	err = utils.EditComponentMessage(itx, buildQueueMessage(entries, sortBy, page))
+	if err != nil {
+		logger.Error("failed to update queue message", "error", err)
+	}
+}
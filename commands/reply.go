@@ -0,0 +1,139 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+func (b *Bot) ReplyCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "reply",
+		Description:         "Send a canned response to the current ticket thread",
+		SlashCommandHandler: b.replyCommandImpl,
+		AutoCompleteHandler: replyAutoComplete,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			{
+				Type:         tempest.STRING_OPTION_TYPE,
+				Name:         "response",
+				Description:  "The canned response to send. Search by name or text",
+				Required:     true,
+				AutoComplete: true,
+			},
+			{
+				Type:         tempest.STRING_OPTION_TYPE,
+				Name:         "language",
+				Description:  "The language to send the response in. Defaults to the language of the ticket's user",
+				AutoComplete: true,
+			},
+			NO_PING_OPTION,
+			PING_OPTION,
+		},
+	}
+}
+
+// Discord shows at most 25 autocomplete choices
+const maxAutoCompleteChoices = 25
+
+// Maximum length of an autocomplete choice's name
+const maxChoiceNameLength = 100
+
+// Suggest the canned responses or languages matching what the helper typed so far
+func replyAutoComplete(itx tempest.CommandInteraction) []tempest.CommandOptionChoice {
+	name, value := itx.GetFocusedValue()
+	query, _ := value.(string)
+
+	if name == "language" {
+		return languageChoices(&itx, query)
+	}
+
+	matches := responses.Search(query, maxAutoCompleteChoices)
+	choices := make([]tempest.CommandOptionChoice, 0, len(matches))
+	for _, r := range matches {
+		name := []rune(r.Name + " — " + r.Description)
+		if len(name) > maxChoiceNameLength {
+			name = append(name[:maxChoiceNameLength-1], '…')
+		}
+
+		choices = append(choices, tempest.CommandOptionChoice{Name: string(name), Value: r.Name})
+	}
+
+	return choices
+}
+
+// Suggest the languages the chosen canned response is translated to, or every language if none is chosen yet
+func languageChoices(itx *tempest.CommandInteraction, query string) []tempest.CommandOptionChoice {
+	var locales []tempest.Language
+	name, _ := itx.GetOptionValue("response")
+	if r, ok := responses.Find(fmt.Sprint(name)); ok {
+		locales = r.Locales()
+	} else {
+		locales = slices.Sorted(maps.Keys(i18n.LocaleNames))
+	}
+
+	query = strings.ToLower(query)
+	choices := []tempest.CommandOptionChoice{}
+	for _, locale := range locales {
+		name := i18n.LocaleNames[locale] + " (" + string(locale) + ")"
+		if !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+
+		choices = append(choices, tempest.CommandOptionChoice{Name: name, Value: string(locale)})
+		if len(choices) == maxAutoCompleteChoices {
+			break
+		}
+	}
+
+	return choices
+}
+
+func (b *Bot) replyCommandImpl(itx *tempest.CommandInteraction) {
+	name, err := utils.GetOption[string](itx, "response", true)
+	if err != nil {
+		return
+	}
+
+	response, ok := responses.Find(name)
+	if !ok {
+		itx.SendLinearReply("I don't know a canned response named `"+name+"`. Pick one of the suggestions.", true)
+		return
+	}
+
+	// /reply has no options for the response's parameters, so only responses with optional ones can be sent with it
+	params := make(responses.Vars, len(response.Parameters))
+	for _, p := range response.Parameters {
+		if p.Required {
+			itx.SendLinearReply("`"+name+"` needs a "+p.Name+", use `/"+name+"` to send it instead.", true)
+			return
+		}
+		params[p.Name] = responses.Text("")
+	}
+
+	var locale tempest.Language
+	if language, err := utils.GetOption[string](itx, "language", false); err == nil {
+		locale = tempest.Language(language)
+		if !i18n.IsLocale(locale) {
+			itx.SendLinearReply("`"+language+"` is not a language Discord supports. Pick one of the suggestions.", true)
+			return
+		}
+	}
+
+	b.sendCannedResponse(itx, response, locale, params)
+}
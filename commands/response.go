@@ -0,0 +1,633 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+var ResponseCommandGroup = tempest.Command{
+	Name:                "response",
+	Description:         "Create, edit and preview the canned responses sent to tickets",
+	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+}
+
+// The option picking an existing canned response
+var cannedResponseOption = tempest.CommandOption{
+	Type:         tempest.STRING_OPTION_TYPE,
+	Name:         "response",
+	Description:  "The canned response. Search by name or text",
+	Required:     true,
+	AutoComplete: true,
+}
+
+func (b *Bot) ResponseCreate() tempest.Command {
+	return tempest.Command{
+		Name:                "create",
+		Description:         "Create a canned response, and a slash command of the same name sending it",
+		SlashCommandHandler: b.responseCreateImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "name",
+				Description: "The name of the slash command sending the response",
+				Required:    true,
+				MinLength:   1,
+				MaxLength:   32,
+			},
+			{
+				Type:        tempest.BOOLEAN_OPTION_TYPE,
+				Name:        "ping",
+				Description: "Whether the user is pinged unless the helper says otherwise. Defaults to true",
+			},
+		},
+	}
+}
+
+func (b *Bot) ResponseEdit() tempest.Command {
+	return tempest.Command{
+		Name:                "edit",
+		Description:         "Edit a canned response",
+		SlashCommandHandler: b.responseEditImpl,
+		AutoCompleteHandler: replyAutoComplete,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			cannedResponseOption,
+			{
+				Type:        tempest.BOOLEAN_OPTION_TYPE,
+				Name:        "ping",
+				Description: "Whether the user is pinged unless the helper says otherwise. Defaults to the current setting",
+			},
+		},
+	}
+}
+
+func (b *Bot) ResponseDelete() tempest.Command {
+	return tempest.Command{
+		Name:                "delete",
+		Description:         "Delete a canned response and its slash command",
+		SlashCommandHandler: b.responseDeleteImpl,
+		AutoCompleteHandler: replyAutoComplete,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options:             []tempest.CommandOption{cannedResponseOption},
+	}
+}
+
+func (b *Bot) ResponseList() tempest.Command {
+	return tempest.Command{
+		Name:                "list",
+		Description:         "List the canned responses, and who last edited them",
+		SlashCommandHandler: b.responseListImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
+}
+
+func (b *Bot) ResponsePreview() tempest.Command {
+	return tempest.Command{
+		Name:                "preview",
+		Description:         "Show a canned response as users would see it, only to you",
+		SlashCommandHandler: b.responsePreviewImpl,
+		AutoCompleteHandler: replyAutoComplete,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			cannedResponseOption,
+			{
+				Type:         tempest.STRING_OPTION_TYPE,
+				Name:         "language",
+				Description:  "The language to preview the response in. Defaults to English",
+				AutoComplete: true,
+			},
+		},
+	}
+}
+
+const CannedResponseModalID = "canned-response-modal"
+
+// How long a helper has to submit the canned response modal
+const cannedResponseModalTimeout = 15 * time.Minute
+
+// Maximum length of a modal's title
+const maxModalTitleLength = 45
+
+// A canned response being created or edited, waiting for the helper to submit the modal.
+// Modals cannot carry state other than their custom ID, so it is kept in the Bot, by helper.
+type pendingCannedResponse struct {
+	response  responses.CannedResponse // The response before the edit, with the name and ping setting chosen in the command
+	create    bool                     // Whether the response is being created rather than edited
+	expiresAt time.Time
+}
+
+// Index of each field of the canned response modal
+const (
+	cannedResponseDescriptionField = iota
+	cannedResponseBodyField
+	cannedResponseInvokerResponseField
+	cannedResponseImageField
+	cannedResponseImageDescriptionField
+)
+
+// Return whether `name` can be used for a canned response, i.e. it is not the name of another command
+func (b *Bot) canUseCannedResponseName(client *tempest.BaseClient, name string) bool {
+	b.cannedCommandsMu.Lock()
+	defer b.cannedCommandsMu.Unlock()
+
+	return !b.isOtherCommand(client, name)
+}
+
+// Return whether `name` is the name of a registered command other than a canned response.
+// Must be called with b.cannedCommandsMu held.
+func (b *Bot) isOtherCommand(client *tempest.BaseClient, name string) bool {
+	_, registered := client.FindCommand(name)
+	return registered && !b.cannedCommands[name]
+}
+
+// Ask the helper for the text of a canned response with a modal, prefilled with its current text
+func (b *Bot) openCannedResponseModal(itx *tempest.CommandInteraction, r responses.CannedResponse, create bool) {
+	title := "Edit /" + r.Name
+	if create {
+		title = "Create /" + r.Name
+	}
+	if titleRunes := []rune(title); len(titleRunes) > maxModalTitleLength {
+		title = string(titleRunes[:maxModalTitleLength])
+	}
+
+	b.pendingCannedResponsesMu.Lock()
+	b.pendingCannedResponses[itx.Member.User.ID] = pendingCannedResponse{
+		response:  r,
+		create:    create,
+		expiresAt: time.Now().Add(cannedResponseModalTimeout),
+	}
+	b.pendingCannedResponsesMu.Unlock()
+
+	err := itx.SendModal(tempest.ResponseModalData{
+		CustomID: CannedResponseModalID,
+		Title:    title,
+		Components: []tempest.LayoutComponent{
+			// Must stay in the order of the cannedResponse*Field constants
+			tempest.LabelComponent{
+				Type:        tempest.LABEL_COMPONENT_TYPE,
+				Label:       "Description",
+				Description: "The description of the slash command",
+				Component: tempest.TextInputComponent{
+					Type:      tempest.TEXT_INPUT_COMPONENT_TYPE,
+					CustomID:  "description",
+					Style:     tempest.SHORT_TEXT_INPUT_STYLE,
+					Value:     r.Description,
+					MaxLength: 100,
+					Required:  true,
+				},
+			},
+			tempest.LabelComponent{
+				Type:        tempest.LABEL_COMPONENT_TYPE,
+				Label:       "Message",
+				Description: "The message sent to the ticket. Can use {user}, {helper}, {ticket_number} and {ticket_age}",
+				Component: tempest.TextInputComponent{
+					Type:      tempest.TEXT_INPUT_COMPONENT_TYPE,
+					CustomID:  "body",
+					Style:     tempest.PARAGRAPH_TEXT_INPUT_STYLE,
+					Value:     r.Body,
+					MaxLength: 2000,
+					Required:  true,
+				},
+			},
+			tempest.LabelComponent{
+				Type:        tempest.LABEL_COMPONENT_TYPE,
+				Label:       "Confirmation",
+				Description: "The message shown to the helper who sent the response",
+				Component: tempest.TextInputComponent{
+					Type:      tempest.TEXT_INPUT_COMPONENT_TYPE,
+					CustomID:  "invoker-response",
+					Style:     tempest.SHORT_TEXT_INPUT_STYLE,
+					Value:     r.InvokerResponse,
+					MaxLength: 2000,
+					Required:  true,
+				},
+			},
+			tempest.LabelComponent{
+				Type:        tempest.LABEL_COMPONENT_TYPE,
+				Label:       "Image URL",
+				Description: "An image shown below the message",
+				Component: tempest.TextInputComponent{
+					Type:     tempest.TEXT_INPUT_COMPONENT_TYPE,
+					CustomID: "image",
+					Style:    tempest.SHORT_TEXT_INPUT_STYLE,
+					Value:    r.Image,
+					Required: false,
+				},
+			},
+			tempest.LabelComponent{
+				Type:        tempest.LABEL_COMPONENT_TYPE,
+				Label:       "Image description",
+				Description: "Alt text of the image",
+				Component: tempest.TextInputComponent{
+					Type:      tempest.TEXT_INPUT_COMPONENT_TYPE,
+					CustomID:  "image-description",
+					Style:     tempest.PARAGRAPH_TEXT_INPUT_STYLE,
+					Value:     r.ImageDescription,
+					MaxLength: 1024,
+					Required:  false,
+				},
+			},
+		},
+	})
+	if err != nil {
+		itx.SendLinearReply("Error: Failed to send the canned response form: "+err.Error(), true)
+	}
+}
+
+// Set the ping setting of the response from the command's `ping` option, if given
+func setPingFromOption(itx *tempest.CommandInteraction, r *responses.CannedResponse) {
+	ping, err := utils.GetOption[bool](itx, "ping", false)
+	if err == nil {
+		r.Ping = &ping
+	}
+}
+
+func (b *Bot) responseCreateImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	name, err := utils.GetOption[string](itx, "name", true)
+	if err != nil {
+		return
+	}
+
+	if _, exists := responses.Find(name); exists {
+		itx.SendLinearReply("A canned response named `"+name+"` already exists, use `/response edit` to change it.", true)
+		return
+	}
+
+	if !b.canUseCannedResponseName(itx.Client, name) {
+		itx.SendLinearReply("`/"+name+"` is already a command, pick another name.", true)
+		return
+	}
+
+	r := responses.CannedResponse{Name: name}
+	setPingFromOption(itx, &r)
+	b.openCannedResponseModal(itx, r, true)
+}
+
+func (b *Bot) responseEditImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	name, err := utils.GetOption[string](itx, "response", true)
+	if err != nil {
+		return
+	}
+
+	r, ok := responses.Find(name)
+	if !ok {
+		itx.SendLinearReply("I don't know a canned response named `"+name+"`. Pick one of the suggestions.", true)
+		return
+	}
+
+	setPingFromOption(itx, &r)
+	b.openCannedResponseModal(itx, r, false)
+}
+
+// HandleCannedResponseModal saves the canned response a helper submitted with /response create or edit
+func (b *Bot) HandleCannedResponseModal(mitx tempest.ModalInteraction) {
+	if mitx.Member == nil || mitx.Member.User == nil {
+		mitx.AcknowledgeWithLinearMessage("Error: Unable to identify user", true)
+		return
+	}
+	authorID := mitx.Member.User.ID
+
+	b.pendingCannedResponsesMu.Lock()
+	pending, ok := b.pendingCannedResponses[authorID]
+	delete(b.pendingCannedResponses, authorID)
+	b.pendingCannedResponsesMu.Unlock()
+
+	if !ok || time.Now().After(pending.expiresAt) {
+		mitx.AcknowledgeWithLinearMessage("This form expired, please run the command again.", true)
+		return
+	}
+
+	r := pending.response
+	r.Description = strings.TrimSpace(getLabelComponent[tempest.TextInputComponent](mitx, cannedResponseDescriptionField).Value)
+	r.Body = getLabelComponent[tempest.TextInputComponent](mitx, cannedResponseBodyField).Value
+	r.InvokerResponse = strings.TrimSpace(getLabelComponent[tempest.TextInputComponent](mitx, cannedResponseInvokerResponseField).Value)
+	r.Image = strings.TrimSpace(getLabelComponent[tempest.TextInputComponent](mitx, cannedResponseImageField).Value)
+	r.ImageDescription = getLabelComponent[tempest.TextInputComponent](mitx, cannedResponseImageDescriptionField).Value
+
+	// A command may have been registered with that name since the form was opened
+	if !b.canUseCannedResponseName(mitx.Client, r.Name) {
+		mitx.AcknowledgeWithLinearMessage("`/"+r.Name+"` is already a command, the canned response was not saved.", true)
+		return
+	}
+
+	// Check the response against the others before saving it, so that an invalid response never makes it to the database
+	candidates := responses.Active()
+	i := slices.IndexFunc(candidates, func(c responses.CannedResponse) bool { return c.Name == r.Name })
+	switch {
+	case i >= 0 && pending.create:
+		mitx.AcknowledgeWithLinearMessage("A canned response named `"+r.Name+"` was created in the meantime, use `/response edit` to change it.", true)
+		return
+	case i >= 0:
+		candidates[i] = r
+	default:
+		candidates = append(candidates, r)
+	}
+
+	err := responses.Validate(candidates)
+	if err != nil {
+		mitx.AcknowledgeWithLinearMessage(truncateMessage("The canned response was not saved:\n"+err.Error()), true)
+		return
+	}
+
+	// Syncing commands with Discord can take longer than Discord waits for a response
+	err = mitx.Defer(true)
+	if err != nil {
+		logging.For(mitx.Interaction).Error("failed to defer canned response modal", "error", err)
+		return
+	}
+
+	var stale []tempest.Language
+	if !pending.create {
+		stale = dropStaleTranslations(&r, pending.response)
+	}
+
+	revision, err := b.saveCannedResponse(mitx.Client, r.Name, &r, authorID)
+	if err != nil {
+		logging.For(mitx.Interaction).Error("failed to save canned response", "response", r.Name, "error", err)
+		if revision == 0 {
+			mitx.SendLinearFollowUp("Failed to save the canned response: "+err.Error(), true)
+		} else {
+			mitx.SendLinearFollowUp(fmt.Sprintf("Saved revision %d of `/%s`, but failed to update the slash commands: %s", revision, r.Name, err), true)
+		}
+		return
+	}
+
+	reply := fmt.Sprintf("Saved revision %d of `/%s`.", revision, r.Name)
+	if len(stale) > 0 {
+		reply += fmt.Sprintf(" Its translations to %s no longer matched the message, so they were removed and these languages get the English message.", formatLocales(stale))
+	}
+	if !pending.create {
+		reply += fmt.Sprintf("\nTranslations and parameters can only be edited in the JSON file of canned responses (`%s`).", filepath.Base(b.Config.CannedResponses.File))
+	}
+	mitx.SendLinearFollowUp(reply, true)
+}
+
+// Remove the translations of the text changed by editing `old` into `r`, so that users in other languages are not
+// sent an outdated message. Returns the locales whose translation was removed or changed, sorted.
+func dropStaleTranslations(r *responses.CannedResponse, old responses.CannedResponse) []tempest.Language {
+	bodyChanged := r.Body != old.Body
+	if !bodyChanged && r.ImageDescription == old.ImageDescription {
+		return nil
+	}
+
+	// The map is shared with the active canned response, so the changes go in a new one
+	var stale []tempest.Language
+	translations := make(map[tempest.Language]responses.Translation, len(r.Translations))
+	for locale, t := range r.Translations {
+		switch {
+		case bodyChanged:
+			stale = append(stale, locale)
+			continue
+		case t.ImageDescription != "":
+			// Falls back to the new untranslated image description
+			t.ImageDescription = ""
+			stale = append(stale, locale)
+		}
+		translations[locale] = t
+	}
+
+	if len(translations) == 0 {
+		translations = nil
+	}
+	r.Translations = translations
+	slices.Sort(stale)
+	return stale
+}
+
+// List locales by their native name, e.g. "Deutsch (de) and Français (fr)"
+func formatLocales(locales []tempest.Language) string {
+	names := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		names = append(names, fmt.Sprintf("%s (%s)", i18n.LocaleNames[locale], locale))
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func (b *Bot) responseDeleteImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	name, err := utils.GetOption[string](itx, "response", true)
+	if err != nil {
+		return
+	}
+
+	if _, ok := responses.Find(name); !ok {
+		itx.SendLinearReply("I don't know a canned response named `"+name+"`. Pick one of the suggestions.", true)
+		return
+	}
+
+	// Syncing commands with Discord can take longer than Discord waits for a response
+	err = itx.Defer(true)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to defer response delete command", "error", err)
+		return
+	}
+
+	revision, err := b.saveCannedResponse(itx.Client, name, nil, itx.Member.User.ID)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to delete canned response", "response", name, "error", err)
+		if revision == 0 {
+			itx.SendLinearReply("Failed to delete the canned response: "+err.Error(), true)
+		} else {
+			itx.SendLinearReply("Deleted `/"+name+"`, but failed to update the slash commands: "+err.Error(), true)
+		}
+		return
+	}
+
+	itx.SendLinearReply(fmt.Sprintf("Deleted `/%s`. It can be restored with `/response create` (revision %d).", name, revision), true)
+}
+
+// Maximum length of a message's content
+const maxMessageLength = 2000
+
+// Truncate a message to fit in Discord's limit
+func truncateMessage(content string) string {
+	runes := []rune(content)
+	if len(runes) <= maxMessageLength {
+		return content
+	}
+
+	return string(runes[:maxMessageLength-1]) + "…"
+}
+
+// Describe who last edited a canned response, or that it comes from the file if it was never edited
+func describeRevision(revision db.CannedResponseRevision, found bool) string {
+	if !found {
+		return "from the canned responses file"
+	}
+
+	return fmt.Sprintf("revision %d by <@%d>, <t:%d:R>", revision.Revision, revision.AuthorID, revision.CreatedAt.Unix())
+}
+
+func (b *Bot) responseListImpl(itx *tempest.CommandInteraction) {
+	revisions, err := b.Store.GetCannedResponses()
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to fetch canned response revisions", "error", err)
+		itx.SendLinearReply("Something went wrong while fetching the canned responses.", true)
+		return
+	}
+
+	latest := make(map[string]db.CannedResponseRevision, len(revisions))
+	for _, revision := range revisions {
+		latest[revision.Name] = revision
+	}
+
+	active := responses.Active()
+	slices.SortFunc(active, func(a, b responses.CannedResponse) int { return strings.Compare(a.Name, b.Name) })
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %d canned responses\n", len(active))
+	for i, r := range active {
+		revision, found := latest[r.Name]
+		line := fmt.Sprintf("- `/%s`: %s\n", r.Name, describeRevision(revision, found))
+
+		// Leave room for the line saying how many were left out
+		if utf8.RuneCountInString(sb.String())+utf8.RuneCountInString(line) > maxMessageLength-20 {
+			fmt.Fprintf(&sb, "…and %d more", len(active)-i)
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         sb.String(),
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, true, nil)
+}
+
+// Number of past revisions shown in a preview
+const previewHistoryLength = 5
+
+func (b *Bot) responsePreviewImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	name, err := utils.GetOption[string](itx, "response", true)
+	if err != nil {
+		return
+	}
+
+	r, ok := responses.Find(name)
+	if !ok {
+		itx.SendLinearReply("I don't know a canned response named `"+name+"`. Pick one of the suggestions.", true)
+		return
+	}
+
+	locale := i18n.DEFAULT_LOCALE
+	if language, err := utils.GetOption[string](itx, "language", false); err == nil {
+		locale = tempest.Language(language)
+		if !i18n.IsLocale(locale) {
+			itx.SendLinearReply("`"+language+"` is not a language Discord supports. Pick one of the suggestions.", true)
+			return
+		}
+	}
+	r, locale = r.Localized(locale)
+
+	history, err := b.Store.GetCannedResponseHistory(name)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to fetch canned response history", "response", name, "error", err)
+	}
+
+	// Fill in the variables with examples, as the preview is not sent in a ticket
+	vars := responses.Vars{
+		responses.VAR_USER:          responses.Raw(itx.Member.User.Mention()),
+		responses.VAR_HELPER:        responses.Text(utils.DisplayName(itx.Member)),
+		responses.VAR_TICKET_NUMBER: responses.Text("123"),
+		responses.VAR_TICKET_AGE:    responses.Raw(fmt.Sprintf("<t:%d:R>", time.Now().Add(-2*time.Hour).Unix())),
+	}
+	for _, p := range r.Parameters {
+		vars[p.Name] = responses.Text("[" + p.Name + "]")
+	}
+
+	body := responses.Render(r.Body, vars)
+	if r.PingByDefault() {
+		body = i18n.Message(locale, i18n.GREETING, itx.Member.User.Mention()) + "\n" + body
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "-# Preview of `/%s` in %s, ", r.Name, i18n.LocaleNames[locale])
+	if len(history) == 0 {
+		header.WriteString(describeRevision(db.CannedResponseRevision{}, false))
+	}
+	for i, revision := range history[:min(len(history), previewHistoryLength)] {
+		if i > 0 {
+			header.WriteString("\n-# ")
+		}
+		if revision.Deleted() {
+			fmt.Fprintf(&header, "deleted by <@%d>, <t:%d:R>", revision.AuthorID, revision.CreatedAt.Unix())
+		} else {
+			header.WriteString(describeRevision(revision, true))
+		}
+	}
+
+	components := []tempest.AnyComponent{
+		tempest.TextDisplayComponent{Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE, Content: header.String()},
+		tempest.TextDisplayComponent{Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE, Content: body},
+	}
+	if r.Image != "" {
+		components = append(components, tempest.MediaGalleryComponent{
+			Type: tempest.MEDIA_GALLERY_COMPONENT_TYPE,
+			Items: []tempest.MediaGalleryItem{{
+				Media:       tempest.UnfurledMediaItem{URL: r.Image},
+				Description: r.ImageDescription,
+			}},
+		})
+	}
+
+	err = itx.SendReply(tempest.ResponseMessageData{
+		Flags:           tempest.IS_COMPONENTS_V2_MESSAGE_FLAG,
+		AllowedMentions: &tempest.AllowedMentions{},
+		Components: []tempest.LayoutComponent{
+			tempest.ContainerComponent{Type: tempest.CONTAINER_COMPONENT_TYPE, Components: components},
+		},
+	}, true, nil)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to send canned response preview", "error", err)
+		itx.SendLinearReply("Failed to show the preview: "+err.Error(), true)
+	}
+}
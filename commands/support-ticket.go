@@ -12,48 +12,65 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
-	"github.com/pagefaultgames/ticketune/constants"
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/config"
 	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/metrics"
+	"github.com/pagefaultgames/ticketune/responses"
 	"github.com/pagefaultgames/ticketune/types"
 	"github.com/pagefaultgames/ticketune/utils"
 
 	"github.com/amatsagu/tempest"
 )
 
-// This command sends a message with an "Open Ticket" button to the channel where the command was invoked
-func supportTicketCmdImpl(itx *tempest.CommandInteraction) {
-	msg := tempest.Message{
-		Flags: tempest.IS_COMPONENTS_V2_MESSAGE_FLAG,
-		Components: []tempest.LayoutComponent{
-			tempest.ContainerComponent{
-				AccentColor: 0x51ff00,
-				Type:        tempest.CONTAINER_COMPONENT_TYPE,
-				// Can't actually be AnyComponent here, must be one of the specific component types
-				// allowed inside a container
-				Components: []tempest.AnyComponent{
-					tempest.TextDisplayComponent{
+// This command sends a message with an "Open Ticket" button for each ticket category (or only the chosen one) to the
+// channel where the command was invoked
+func (b *Bot) supportTicketCmdImpl(itx *tempest.CommandInteraction) {
+	categories := b.Config.TicketCategories()
+	if id, err := utils.GetOption[string](itx, "category", false); err == nil {
+		category, ok := b.Config.TicketCategory(id)
+		if !ok {
+			itx.SendLinearReply("Unknown ticket category: "+id, true)
+			return
+		}
+		categories = []config.Category{category}
+	}
+
+	msg := tempest.Message{Flags: tempest.IS_COMPONENTS_V2_MESSAGE_FLAG}
+	for _, category := range categories {
+		msg.Components = append(msg.Components, tempest.ContainerComponent{
+			AccentColor: 0x51ff00,
+			Type:        tempest.CONTAINER_COMPONENT_TYPE,
+			// Can't actually be AnyComponent here, must be one of the specific component types
+			// allowed inside a container
+			Components: []tempest.AnyComponent{
+				tempest.TextDisplayComponent{
+					Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+					Content: "# " + category.Name,
+				},
+				tempest.SectionComponent{
+					Type: tempest.SECTION_COMPONENT_TYPE,
+					Components: []tempest.TextDisplayComponent{{
 						Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
-						Content: "# Forgotten Password Support",
-					},
-					tempest.SectionComponent{
-						Type: tempest.SECTION_COMPONENT_TYPE,
-						Components: []tempest.TextDisplayComponent{{
-							Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
-							Content: "Forgot your password? Click the button to open a support ticket.",
-						}},
-						Accessory: tempest.ButtonComponent{
-							Type:     tempest.BUTTON_COMPONENT_TYPE,
-							CustomID: "open-ticket-button",
-							Label:    "Open Ticket",
-							Style:    tempest.PRIMARY_BUTTON_STYLE,
-						},
+						Content: category.Description,
+					}},
+					Accessory: tempest.ButtonComponent{
+						Type:     tempest.BUTTON_COMPONENT_TYPE,
+						CustomID: OpenTicketButtonID(category.ID),
+						Label:    category.ButtonLabel,
+						Style:    tempest.PRIMARY_BUTTON_STYLE,
 					},
 				},
 			},
-		}}
+		})
+	}
 
 	channel, presence := itx.GetOptionValue("channel")
 
@@ -76,24 +93,39 @@ func supportTicketCmdImpl(itx *tempest.CommandInteraction) {
 	}
 }
 
-// Create the command
-var CreateSupportTicketCommand = tempest.Command{
-	Name:                "send-ticket-message",
-	Description:         "Send a message with an Open Ticket button to the specified channel",
-	SlashCommandHandler: supportTicketCmdImpl,
-	// By default, only let admins use the command.
-	// This is not a hard restriction imposed by the bot, but is simply the permission level
-	// that shows up in the Discord UI. Guild admins can tweak this command to allow other roles to use it.
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
-	GuildID:             constants.DISCORD_GUILD_ID,
-	Options: []tempest.CommandOption{{
-		Type:         tempest.CHANNEL_OPTION_TYPE,
-		Name:         "channel",
-		Description:  "Channel to send the ticket message to",
-		Required:     true,
-		ChannelTypes: []tempest.ChannelType{tempest.GUILD_TEXT_CHANNEL_TYPE},
-	}},
+// Create the command, offering the configured ticket categories as choices
+func (b *Bot) CreateSupportTicketCommand() tempest.Command {
+	categories := b.Config.TicketCategories()
+	choices := make([]tempest.CommandOptionChoice, 0, len(categories))
+	for _, category := range categories {
+		choices = append(choices, tempest.CommandOptionChoice{Name: category.Name, Value: category.ID})
+	}
+
+	return tempest.Command{
+		Name:                "send-ticket-message",
+		Description:         "Send a message with an Open Ticket button to the specified channel",
+		SlashCommandHandler: b.supportTicketCmdImpl,
+		// By default, only let admins use the command.
+		// This is not a hard restriction imposed by the bot, but is simply the permission level
+		// that shows up in the Discord UI. Guild admins can tweak this command to allow other roles to use it.
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			{
+				Type:         tempest.CHANNEL_OPTION_TYPE,
+				Name:         "channel",
+				Description:  "Channel to send the ticket message to",
+				Required:     true,
+				ChannelTypes: []tempest.ChannelType{tempest.GUILD_TEXT_CHANNEL_TYPE},
+			},
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "category",
+				Description: "Only show the button of this ticket category. Shows every category if not set",
+				Choices:     choices,
+			},
+		},
+	}
 }
 
 // Return whether the user is a member of the thread
@@ -109,10 +141,10 @@ func checkIfUserIsMemberOfThread(client *tempest.BaseClient, threadID, userID te
 	return err == nil
 }
 
-// Return whether there is an open, non-locked ticket for the user
-func checkIfOpenTicketExists(client *tempest.BaseClient, userID tempest.Snowflake) (bool, tempest.Snowflake, error) {
+// Return whether there is an open, non-locked ticket for the user in the category
+func (b *Bot) checkIfOpenTicketExists(client *tempest.BaseClient, userID tempest.Snowflake, category string) (bool, tempest.Snowflake, error) {
 	// Get the thread ID from the database
-	tid, err := db.Get().GetUserThread(userID)
+	tid, err := b.Store.GetUserThread(userID, category)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, 0, nil
@@ -142,10 +174,15 @@ func checkIfOpenTicketExists(client *tempest.BaseClient, userID tempest.Snowflak
 	return true, tid, nil
 }
 
-// Respond to the interaction with an ephemeral message containing the link to the created thread
-func sendAlreadyCreatedTicketMessage(itx *tempest.ComponentInteraction, threadID tempest.Snowflake) error {
+// The interactions tickets are opened from, which are answered the same way
+type ticketInteraction interface {
+	AcknowledgeWithMessage(reply tempest.ResponseMessageData, ephemeral bool) error
+}
+
+// Respond to the interaction with an ephemeral message containing the link to the existing thread
+func sendAlreadyCreatedTicketMessage(itx ticketInteraction, locale tempest.Language, threadID tempest.Snowflake) error {
 	err := itx.AcknowledgeWithMessage(tempest.ResponseMessageData{
-		Content: fmt.Sprintf("I found an existing ticket, try using this: <#%d>", threadID),
+		Content: i18n.Message(locale, i18n.TICKET_ALREADY_EXISTS, threadID),
 	}, true)
 	if err != nil {
 		return err
@@ -154,111 +191,280 @@ func sendAlreadyCreatedTicketMessage(itx *tempest.ComponentInteraction, threadID
 	return nil
 }
 
-// "I was unable to create your support ticket. Please try again..."
-var couldNotCreateThread = fmt.Sprintf(
-	"I was unable to create your support ticket. Please try again.\n"+
-		"If this issue persists, please reach out to someone in <#%d>.",
-	constants.BOT_TROUBLESHOOTING_CHANNEL_ID,
-)
+// Acknowledge the interaction with an error message, asking the user to reach out in the troubleshooting channel
+func (b *Bot) acknowledgeErrorMessage(itx ticketInteraction, locale tempest.Language, id i18n.MessageID) {
+	itx.AcknowledgeWithMessage(tempest.ResponseMessageData{
+		Content: i18n.Message(locale, id, b.Config.Discord.TroubleshootingChannelID),
+	}, true)
+}
 
-// "I was unable to add you to the support ticket..."
-var couldNotAddToThread = fmt.Sprintf(
-	"I created support thread, but something went wrong while trying to give you access to it. "+
-		"Please reach out to someone in <#%d> for help, and mention that I was unable to give you access to your password reset ticket.",
-	constants.BOT_TROUBLESHOOTING_CHANNEL_ID,
-)
+// Return the locale to talk to the user in, from the locale of their Discord client
+func interactionLocale(itx *tempest.Interaction) tempest.Language {
+	if i18n.IsLocale(itx.Locale) {
+		return itx.Locale
+	}
 
-// "something went wrong while trying to send the instructions..."
-var couldNotSendInstruction = fmt.Sprintf(
-	"I created your support ticket and added you to it, "+
-		"but something went wrong while trying to send the instructions. Please reach out to someone in <#%d>, and mention that I could not send the instructions.",
-	constants.BOT_TROUBLESHOOTING_CHANNEL_ID)
+	return i18n.DEFAULT_LOCALE
+}
 
-// "Something went wrong, I couldn't get your user ID..."
-var couldNotGetUserID = fmt.Sprintf(
-	"Something went wrong, I couldn't get your user ID. Please try again, and if the issue persists, reach out to someone in <#%d>.",
-	constants.BOT_TROUBLESHOOTING_CHANNEL_ID,
-)
+// Return the ticket category of an Open Ticket button or intake form, answering the interaction with an error if it
+// is no longer configured
+func (b *Bot) interactionCategory(logger *slog.Logger, reply ticketInteraction, locale tempest.Language, customID string) (config.Category, bool) {
+	id := categoryFromCustomID(customID)
+	category, ok := b.Config.TicketCategory(id)
+	if !ok {
+		// The button was sent before its category was removed from the configuration
+		logger.Warn("refused ticket in unknown category", "category", id)
+		b.acknowledgeErrorMessage(reply, locale, i18n.COULD_NOT_CREATE_THREAD)
+	}
 
-// Acknowledge the interaction with a generic error message
-func acknowledgeErrorMessage(itx *tempest.ComponentInteraction, content string) {
-	itx.AcknowledgeWithMessage(tempest.ResponseMessageData{
-		Content: content,
-	}, true)
+	return category, ok
 }
 
 // This function will be used at every button click, there's no max time limit.
-func OpenTicketButtonCallback(itx tempest.ComponentInteraction) {
+// Shows the intake form of the button's category, unless the user may not open a ticket; the ticket is opened once
+// the form is submitted. Categories without a form open the ticket right away.
+func (b *Bot) OpenTicketButtonCallback(itx tempest.ComponentInteraction) {
+	locale := interactionLocale(itx.Interaction)
+	logger := logging.For(itx.Interaction)
+
 	// Get member. If member is nil, something went wrong, because this can only be used in guilds
 	if itx.Member == nil || itx.Member.User == nil {
 		// Should not happen as long as discord payload is not corrupted
-		itx.AcknowledgeWithMessage(tempest.ResponseMessageData{Content: couldNotGetUserID}, true)
+		b.acknowledgeErrorMessage(itx, locale, i18n.COULD_NOT_GET_USER_ID)
+		return
+	}
+
+	category, ok := b.interactionCategory(logger, itx, locale, itx.Data.CustomID)
+	if !ok {
+		return
+	}
+
+	questions := intakeQuestions(category, locale)
+	if len(questions) == 0 {
+		if !b.refuseTicket(logger, itx.Interaction, itx, locale, category) {
+			b.openTicket(logger, itx.Interaction, itx, locale, category, nil)
+		}
+		return
+	}
+
+	// Don't make users fill in the form to then refuse their ticket
+	if b.refuseTicket(logger, itx.Interaction, itx, locale, category) {
 		return
 	}
 
+	err := itx.AcknowledgeWithModal(intakeModal(category, questions, locale))
+	if err != nil {
+		logger.Error("failed to show intake form", "error", err)
+	}
+}
+
+// Open a ticket with the answers of the intake form
+func (b *Bot) HandleOpenTicketModal(itx tempest.ModalInteraction) {
+	locale := interactionLocale(itx.Interaction)
+	logger := logging.For(itx.Interaction)
+
+	if itx.Member == nil || itx.Member.User == nil {
+		b.acknowledgeErrorMessage(itx, locale, i18n.COULD_NOT_GET_USER_ID)
+		return
+	}
+
+	category, ok := b.interactionCategory(logger, itx, locale, itx.Data.CustomID)
+	if !ok {
+		return
+	}
+
+	// Checked again, as the user may have opened a ticket from another form in the meantime
+	if b.refuseTicket(logger, itx.Interaction, itx, locale, category) {
+		return
+	}
+
+	b.openTicket(logger, itx.Interaction, itx, locale, category, intakeAnswers(itx, intakeQuestions(category, locale)))
+}
+
+// Refuse to open a ticket in the category for the user if they are banned, already have one in it, opened one in it
+// too recently, or if too many tickets were opened recently, answering the interaction with why. Returns whether the
+// ticket was refused. The ticket is only counted against the rate limit once openTicket opens it.
+func (b *Bot) refuseTicket(logger *slog.Logger, itx *tempest.Interaction, reply ticketInteraction, locale tempest.Language, category config.Category) bool {
+	userID := itx.Member.User.ID
+	now := time.Now()
+
+	// On error, proceed as though the user is not banned
+	ban, err := b.Store.GetTicketBan(userID)
+	if err == nil && ban.Active(now) {
+		logger.Info("refused ticket to banned user")
+		metrics.TicketsRefused.WithLabelValues(metrics.REFUSED_BANNED).Inc()
+		reply.AcknowledgeWithMessage(tempest.ResponseMessageData{
+			Content: b.ticketBannedMessage(locale, ban),
+		}, true)
+		return true
+	} else if err != nil && err != sql.ErrNoRows {
+		logger.Error("failed to look up the user's ticket ban", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to look up whether a user is banned from opening tickets",
+			Err:         err,
+			Interaction: itx,
+		})
+	}
+
+	// On error, proceed as though no ticket exists
+	exists, tid, err := b.checkIfOpenTicketExists(itx.Client, userID, category.ID)
+	if err != nil {
+		logger.Error("failed to look up the user's open ticket", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to look up whether a user has an open ticket",
+			Err:         err,
+			Interaction: itx,
+		})
+	}
+	if exists {
+		sendAlreadyCreatedTicketMessage(reply, locale, tid)
+		return true
+	}
+
+	// Checked after looking for an open ticket, so that users are still sent the link to theirs.
+	// On error, proceed as though the user never opened a ticket.
+	cooldownEnd, cooling, err := b.ticketCooldownEnd(userID, category.ID, now)
+	if err != nil {
+		logger.Error("failed to look up the user's last ticket", "error", err)
+	}
+	if cooling {
+		logger.Info("refused ticket to user on cooldown", "category", category.ID, "cooldown_end", cooldownEnd)
+		metrics.TicketsRefused.WithLabelValues(metrics.REFUSED_COOLDOWN).Inc()
+		reply.AcknowledgeWithMessage(tempest.ResponseMessageData{
+			Content: i18n.Message(locale, i18n.TICKET_COOLDOWN, cooldownEnd.Unix()),
+		}, true)
+		return true
+	}
+
+	retryAt, ok := b.ticketLimiter.available(now, b.Config.Cooldown.GlobalLimit, b.Config.Cooldown.GlobalInterval())
+	if !ok {
+		refuseRateLimitedTicket(logger, reply, locale, retryAt)
+		return true
+	}
+
+	return false
+}
+
+// Tell the user too many tickets were opened recently, and when they may open theirs
+func refuseRateLimitedTicket(logger *slog.Logger, reply ticketInteraction, locale tempest.Language, retryAt time.Time) {
+	logger.Warn("refused ticket as too many were opened recently", "retry_at", retryAt)
+	metrics.TicketsRefused.WithLabelValues(metrics.REFUSED_RATE_LIMITED).Inc()
+	reply.AcknowledgeWithMessage(tempest.ResponseMessageData{
+		Content: i18n.Message(locale, i18n.TICKET_RATE_LIMITED, retryAt.Unix()),
+	}, true)
+}
+
+// Open a ticket in the category for the user of the interaction: create its thread, give the user access to it, and
+// post the welcome message and the user's intake form answers in it.
+// The ticket is counted against the global rate limit while it is opened, and no longer if it could not be.
+func (b *Bot) openTicket(logger *slog.Logger, itx *tempest.Interaction, reply ticketInteraction, locale tempest.Language, category config.Category, answers []db.IntakeAnswer) {
 	user := itx.Member.User
 	userID := user.ID
 
-	// Discard any errors from checkIfOpenTicketExists, proceeding as though no ticket
-	// exists.
-	exists, tid, _ := checkIfOpenTicketExists(itx.Client, userID)
-	if exists {
-		sendAlreadyCreatedTicketMessage(&itx, tid)
+	// Reserved before creating the thread, so that tickets opened at the same time can't go over the limit together
+	reservedAt := time.Now()
+	retryAt, ok := b.ticketLimiter.reserve(reservedAt, b.Config.Cooldown.GlobalLimit, b.Config.Cooldown.GlobalInterval())
+	if !ok {
+		refuseRateLimitedTicket(logger, reply, locale, retryAt)
 		return
 	}
+	release := sync.OnceFunc(func() { b.ticketLimiter.release(reservedAt) })
 
-	threadID, err := createThread(itx.Client, constants.TICKET_CHANNEL_ID, fmt.Sprintf("Password Help - %s", user.Username))
+	threadID, err := createThread(itx.Client, category.ChannelID, ticketThreadName(category.ThreadName, user.Username, ""))
 	if err != nil {
-		log.Println("failed to create thread", err)
+		logger.Error("failed to create ticket thread", "category", category.ID, "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_PERMISSIONS,
+			Summary:     "Failed to create a ticket thread",
+			Fix:         fmt.Sprintf("Check that the bot can create private threads in <#%d>.", category.ChannelID),
+			Err:         err,
+			Interaction: itx,
+		})
 		// Notify the user that we failed to create the thread
-		acknowledgeErrorMessage(&itx, couldNotCreateThread)
+		b.acknowledgeErrorMessage(reply, locale, i18n.COULD_NOT_CREATE_THREAD)
+		release()
 		return
 	}
 
 	// Set the user thread if we were able to create it, regardless if we successfully added them.
 	// This ensures users cannot spam the button to create multiple threads, even if the bot ran into some issue..
-	err = db.Get().SetUserThread(userID, threadID)
-	// TODO: When this happens, send a message to some channel saying something went wrong with DB
+	ticketNumber, err := b.Store.OpenTicket(userID, category.ID, threadID, locale)
 	if err != nil {
-		log.Println("failed to save thread to database", err)
+		logger.Error("failed to save ticket to database", "thread_id", threadID.String(), "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to save a new ticket to the database",
+			Fix:         fmt.Sprintf("The bot does not know about <#%d>, so `/close` will not work in it: delete it by hand once the user is helped.", threadID),
+			Err:         err,
+			Interaction: itx,
+		})
+		// The user may open another ticket, as this one is not saved
+		release()
+	} else {
+		logger = logging.WithTicket(itx, ticketNumber)
+
+		// The answers are also posted in the thread, so losing them is not worth stopping for
+		err = b.Store.SaveTicketIntake(ticketNumber, answers)
+		if err != nil {
+			logger.Error("failed to save intake form answers", "error", err)
+		}
 	}
 
 	// Give the user permission to view and send messages in threads in the ticket channel
-	err = giveUserTicketChannelPerms(itx.Client, userID)
+	err = giveUserTicketChannelPerms(itx.Client, category.ChannelID, userID)
 	if err != nil {
-		log.Println("failed to give user ticket channel perms", err)
-		acknowledgeErrorMessage(&itx, couldNotAddToThread)
+		logger.Error("failed to give user ticket channel permissions", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_PERMISSIONS,
+			Summary:     "Failed to give a user access to the ticket channel",
+			Fix:         fmt.Sprintf("Check that the bot can manage the permissions of <#%d>, and give the user access to <#%d> by hand.", category.ChannelID, threadID),
+			Err:         err,
+			Interaction: itx,
+			Ticket:      ticketNumber,
+		})
+		b.acknowledgeErrorMessage(reply, locale, i18n.COULD_NOT_ADD_TO_THREAD)
 	}
 
 	// Add the user to the thread
 	err = addMemberToThread(itx.Client, threadID, userID)
 	// An error here generally means the bot has insufficient permissions to add the user to the thread
 	if err != nil {
-		log.Println("failed to add member to thread", err)
-		acknowledgeErrorMessage(&itx, couldNotAddToThread)
+		logger.Error("failed to add user to ticket thread", "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_PERMISSIONS,
+			Summary:     "Failed to add a user to their ticket thread",
+			Fix:         fmt.Sprintf("Mention the user in <#%d> to add them to it.", threadID),
+			Err:         err,
+			Interaction: itx,
+			Ticket:      ticketNumber,
+		})
+		b.acknowledgeErrorMessage(reply, locale, i18n.COULD_NOT_ADD_TO_THREAD)
+		release()
 		return
 	}
 
-	err = sendPostTicketCreatedMessage(&itx, threadID)
+	err = sendPostTicketCreatedMessage(reply, locale, threadID)
 	if err != nil {
 		// This code path means that the bot was not able to reply with a simple message.
 		// There's nothing we can do to communicate with the user, but they would have still had a ticket opened.
 		// Proceed to try to send the instructions message, but log the error
-		log.Println("failed to send post ticket created message", err)
+		logger.Error("failed to send ticket created message", "error", err)
 	}
 
-	// TODO: Change this to a modal?
-	err = sendSupportTicketMessage(itx.Client, threadID, user)
+	err = sendSupportTicketMessage(itx.Client, threadID, user, locale, category, answers)
 	if err != nil {
-		log.Println("failed to send instruction message", err)
-		acknowledgeErrorMessage(&itx, couldNotSendInstruction)
+		logger.Error("failed to send instructions message", "error", err)
+		b.acknowledgeErrorMessage(reply, locale, i18n.COULD_NOT_SEND_INSTRUCTIONS)
 	}
 }
 
 // Respond to the interaction with an ephemeral message containing the link to the created thread
-func sendPostTicketCreatedMessage(itx *tempest.ComponentInteraction, threadID tempest.Snowflake) error {
+func sendPostTicketCreatedMessage(itx ticketInteraction, locale tempest.Language, threadID tempest.Snowflake) error {
 	err := itx.AcknowledgeWithMessage(tempest.ResponseMessageData{
-		Content: fmt.Sprintf("A new ticket has been created: <#%d>", threadID),
+		Content: i18n.Message(locale, i18n.TICKET_CREATED, threadID),
 	}, true)
 	if err != nil {
 		return err
@@ -267,6 +473,25 @@ func sendPostTicketCreatedMessage(itx *tempest.ComponentInteraction, threadID te
 	return nil
 }
 
+// Maximum length of a thread name
+const maxThreadNameLength = 100
+
+// Return the name of the ticket thread for a user from the category's pattern, including the name of the helper working
+// on it (if not empty)
+func ticketThreadName(pattern string, username string, helperName string) string {
+	name := responses.Render(pattern, responses.Vars{"username": responses.Raw(username)})
+	if helperName != "" {
+		name += " (" + helperName + ")"
+	}
+
+	runes := []rune(name)
+	if len(runes) > maxThreadNameLength {
+		name = string(runes[:maxThreadNameLength])
+	}
+
+	return name
+}
+
 // Create a thread in the given channel, with the provided name
 // https://discord.com/developers/docs/resources/channel#start-thread-without-message
 func createThread(client *tempest.BaseClient, channelID tempest.Snowflake, threadName string) (tempest.Snowflake, error) {
@@ -307,44 +532,79 @@ func addMemberToThread(client *tempest.BaseClient, threadID, userID tempest.Snow
 	return nil
 }
 
-// Send the support ticket message to the specified thread
-func sendSupportTicketMessage(client *tempest.BaseClient, threadId tempest.Snowflake, user *tempest.User) error {
+// Send the welcome message of the category to the specified thread, with the summary of the user's intake form answers
+// for helpers. The built-in password instructions are sent in the user's locale.
+func sendSupportTicketMessage(client *tempest.BaseClient, threadId tempest.Snowflake, user *tempest.User, locale tempest.Language, category config.Category, answers []db.IntakeAnswer) error {
+	var components []tempest.AnyComponent
+	switch {
+	case category.ID == config.PASSWORD_CATEGORY && category.WelcomeMessage == "":
+		components = append(components,
+			tempest.TextDisplayComponent{
+				Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+				Content: i18n.Message(locale, i18n.TICKET_INSTRUCTIONS, user.Mention()),
+			},
+			tempest.MediaGalleryComponent{
+				Type: tempest.MEDIA_GALLERY_COMPONENT_TYPE,
+				Items: []tempest.MediaGalleryItem{{
+					Media: tempest.UnfurledMediaItem{
+						URL: "https://raw.githubusercontent.com/pagefaultgames/ticketune/refs/heads/main/assets/gearIcon.png",
+					},
+					Description: i18n.Message(locale, i18n.TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION),
+				}},
+			},
+		)
+	case category.WelcomeMessage == "":
+		components = append(components, tempest.TextDisplayComponent{
+			Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+			Content: i18n.Message(locale, i18n.GREETING, user.Mention()),
+		})
+	default:
+		components = append(components, tempest.TextDisplayComponent{
+			Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+			Content: responses.Render(category.WelcomeMessage, responses.Vars{
+				"user":     responses.Raw(user.Mention()),
+				"username": responses.Text(user.Username),
+			}),
+		})
+	}
+
+	if len(answers) > 0 {
+		components = append(components,
+			tempest.SeparatorComponent{
+				Type:    tempest.SEPARATOR_COMPONENT_TYPE,
+				Divider: true,
+			},
+			tempest.TextDisplayComponent{
+				Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+				Content: intakeSummary(answers),
+			},
+		)
+	}
+
+	components = append(components,
+		tempest.TextDisplayComponent{
+			Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+			Content: fmt.Sprintf("<@&%d>! A new **%s** ticket needs help.", category.HelperRoleID, responses.EscapeMarkdown(category.Name)),
+		},
+		tempest.ActionRowComponent{
+			Type: tempest.ACTION_ROW_COMPONENT_TYPE,
+			Components: []tempest.InteractiveComponent{
+				tempest.ButtonComponent{
+					Type:     tempest.BUTTON_COMPONENT_TYPE,
+					CustomID: ClaimTicketButtonID,
+					Label:    "Claim",
+					Style:    tempest.SECONDARY_BUTTON_STYLE,
+				},
+			},
+		},
+	)
+
 	msg := tempest.Message{
 		Flags: tempest.IS_COMPONENTS_V2_MESSAGE_FLAG,
 		Components: []tempest.LayoutComponent{
 			tempest.ContainerComponent{
-				Type: tempest.CONTAINER_COMPONENT_TYPE,
-				Components: []tempest.AnyComponent{
-					tempest.TextDisplayComponent{
-						Type: tempest.TEXT_DISPLAY_COMPONENT_TYPE,
-						Content: fmt.Sprintf(
-							"### Hello %s!\n"+
-								"Please provide a screenshot of the login page __with the usernames panel open or the error code it might display__.\n"+
-								"You need to __click on the gear in the top left corner__ (see attached image for where to find that)!\n"+
-								"**Please keep in mind that we are real people volunteering our time, so please don't ping us over and over. "+
-								"When someone is free, they'll reach out to help you, but until then, please be patient and wait until "+
-								"we get back to you.**\n\n"+
-								"This process will link your PokéRogue account with the Discord account you used to open this ticket, allowing you to log in without "+
-								"needing your password.\n"+
-								"We have __no way__ to access, check, change, or reset your password, however once you are logged in you are able to change it in the menu yourself.\n"+
-								"Also, **NEVER** give out personal details such as passwords anywhere and to anyone, including in these threads.",
-							user.Mention(),
-						),
-					},
-					tempest.MediaGalleryComponent{
-						Type: tempest.MEDIA_GALLERY_COMPONENT_TYPE,
-						Items: []tempest.MediaGalleryItem{{
-							Media: tempest.UnfurledMediaItem{
-								URL: "https://raw.githubusercontent.com/pagefaultgames/ticketune/refs/heads/main/assets/gearIcon.png",
-							},
-							Description: "Image showing the location of the usernames panel",
-						}},
-					},
-					tempest.TextDisplayComponent{
-						Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
-						Content: fmt.Sprintf("<@&%d>! Please help with the password reset request.", constants.HELPER_ROLE_ID),
-					},
-				},
+				Type:       tempest.CONTAINER_COMPONENT_TYPE,
+				Components: components,
 			},
 		},
 	}
@@ -358,10 +618,10 @@ func sendSupportTicketMessage(client *tempest.BaseClient, threadId tempest.Snowf
 }
 
 // Give the user ID permissions to view, send messages in threads, and read message history in the ticket channel
-func giveUserTicketChannelPerms(client *tempest.BaseClient, userID tempest.Snowflake) error {
+func giveUserTicketChannelPerms(client *tempest.BaseClient, channelID, userID tempest.Snowflake) error {
 	_, err := client.Rest.Request(
 		http.MethodPut,
-		fmt.Sprintf("/channels/%d/permissions/%d", constants.TICKET_CHANNEL_ID, userID),
+		fmt.Sprintf("/channels/%d/permissions/%d", channelID, userID),
 		types.EditChannelPermissionsParams{
 			Allow: tempest.SEND_MESSAGES_IN_THREADS_PERMISSION_FLAG | tempest.VIEW_CHANNEL_PERMISSION_FLAG | tempest.READ_MESSAGE_HISTORY_PERMISSION_FLAG,
 			Type:  types.MEMBER_TYPE,
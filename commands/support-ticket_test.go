@@ -0,0 +1,161 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/discordtest"
+	"github.com/pagefaultgames/ticketune/i18n"
+
+	"github.com/amatsagu/tempest"
+)
+
+func TestOpenTicket(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	thread := b.openTicket()
+	if thread.Name != "Password Help - player" {
+		t.Errorf("the ticket thread is named %q, want %q", thread.Name, "Password Help - player")
+	}
+	if !slices.Contains(b.srv.ThreadMembers(thread.ID), testUser.ID) {
+		t.Error("the user was not added to the ticket thread")
+	}
+	if _, ok := b.srv.Permissions(testTicketChannelID, testUser.ID); !ok {
+		t.Error("the user was not given access to the ticket channel")
+	}
+
+	ticket, err := b.store.GetThreadTicket(thread.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.UserID != testUser.ID || ticket.Status != db.TICKET_OPEN || ticket.Category != config.PASSWORD_CATEGORY {
+		t.Errorf("the ticket was saved as %+v, want an open password ticket of the user", ticket)
+	}
+
+	answers, err := b.store.GetTicketIntake(ticket.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != len(passwordIntakeQuestions) || answers[0] != (db.IntakeAnswer{Question: "Username", Answer: "player123"}) {
+		t.Errorf("the intake form answers were saved as %+v", answers)
+	}
+}
+
+func TestOpenTicketButtonWithOpenTicket(t *testing.T) {
+	b := newTestBot(t, nil)
+	thread := b.openTicket()
+
+	response := b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	want := i18n.Message(i18n.DEFAULT_LOCALE, i18n.TICKET_ALREADY_EXISTS, thread.ID)
+	if response.Type != tempest.CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE || response.Data.Content != want {
+		t.Errorf("the Open Ticket button answered %+v, want %q", response, want)
+	}
+	if threads := b.srv.Threads(testTicketChannelID); len(threads) != 1 {
+		t.Errorf("%d ticket threads were created, want 1", len(threads))
+	}
+}
+
+func TestOpenTicketButtonBanned(t *testing.T) {
+	b := newTestBot(t, nil)
+	err := b.store.BanUser(db.TicketBan{UserID: testUser.ID, Reason: "spam", BannedBy: testHelper.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	want := i18n.Message(i18n.DEFAULT_LOCALE, i18n.TICKET_BANNED)
+	if response.Type != tempest.CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE || response.Data.Content != want {
+		t.Errorf("the Open Ticket button answered %+v, want %q", response, want)
+	}
+
+	// The form was shown before the ban, so submitting it is refused too
+	response = b.send(discordtest.ModalInteraction(userOrigin(), OpenTicketModalID(config.PASSWORD_CATEGORY),
+		textInput("intake-username", "player123"),
+	))
+	if response.Data.Content != want {
+		t.Errorf("submitting the intake form answered %+v, want %q", response, want)
+	}
+	if threads := b.srv.Threads(testTicketChannelID); len(threads) != 0 {
+		t.Errorf("%d ticket threads were created for a banned user", len(threads))
+	}
+}
+
+func TestOpenTicketButtonCooldown(t *testing.T) {
+	b := newTestBot(t, func(cfg *config.Config) {
+		cfg.Cooldown.UserMinutes = 10
+	})
+	thread := b.openTicket()
+
+	_, err := b.store.CloseThread(thread.ID, testHelper.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	prefix, _, _ := strings.Cut(i18n.Message(i18n.DEFAULT_LOCALE, i18n.TICKET_COOLDOWN, 0), "<t:")
+	if response.Type != tempest.CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE || !strings.HasPrefix(response.Data.Content, prefix) {
+		t.Errorf("the Open Ticket button answered %+v, want the cooldown message", response)
+	}
+}
+
+func TestOpenTicketButtonCooldownIsPerCategory(t *testing.T) {
+	b := newTestBot(t, func(cfg *config.Config) {
+		cfg.Cooldown.UserMinutes = 10
+		cfg.Categories = []config.Category{
+			{ID: config.PASSWORD_CATEGORY, Name: "Password", ChannelID: testTicketChannelID},
+			{ID: "bug", Name: "Bug", ChannelID: testTicketChannelID},
+		}
+	})
+	b.openTicket()
+
+	// The bug category has no intake form, so the button opens the ticket right away
+	response := b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID("bug")))
+	if response.Type != tempest.CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE || !strings.HasPrefix(response.Data.Content, "A new ticket has been created") {
+		t.Errorf("the Open Ticket button of another category answered %+v, want a new ticket", response)
+	}
+
+	ok := b.srv.Wait(testTimeout, func() bool {
+		tickets, err := b.store.GetUserTickets(testUser.ID)
+		return err == nil && len(tickets) == 2
+	})
+	if !ok {
+		t.Fatal("the user does not have a ticket in each category")
+	}
+	if threads := b.srv.Threads(testTicketChannelID); len(threads) != 2 || threads[1].Name != fmt.Sprintf("Ticket - %s", testUser.Username) {
+		t.Errorf("want a second thread named after the default pattern, got %+v", threads)
+	}
+}
+
+// A ticket whose thread could not be created does not count against the global rate limit
+func TestOpenTicketFailureReleasesRateLimit(t *testing.T) {
+	b := newTestBot(t, func(cfg *config.Config) {
+		cfg.Cooldown.GlobalLimit = 1
+	})
+	b.srv.Fail(http.MethodPost, fmt.Sprintf("/channels/%d/threads", testTicketChannelID), http.StatusForbidden)
+
+	b.send(discordtest.ComponentInteraction(userOrigin(), OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	response := b.send(discordtest.ModalInteraction(userOrigin(), OpenTicketModalID(config.PASSWORD_CATEGORY),
+		textInput("intake-username", "player123"),
+		textInput("intake-platform", "Android, Chrome"),
+	))
+	if want := i18n.Message(i18n.DEFAULT_LOCALE, i18n.COULD_NOT_CREATE_THREAD, testTroubleshootingID); response.Data.Content != want {
+		t.Fatalf("opening a ticket answered %q, want %q", response.Data.Content, want)
+	}
+
+	if retryAt, ok := b.ticketLimiter.available(time.Now(), 1, b.Config.Cooldown.GlobalInterval()); !ok {
+		t.Errorf("the failed ticket still counts against the rate limit, tickets may be opened again at %v", retryAt)
+	}
+}
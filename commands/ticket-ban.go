@@ -0,0 +1,239 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pagefaultgames/ticketune/alerts"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/responses"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+var TicketBanCommandGroup = tempest.Command{
+	Name:                "ticket-ban",
+	Description:         "Ban users from opening tickets",
+	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+	Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+}
+
+// The option picking the user to ban or unban
+var ticketBanUserOption = tempest.CommandOption{
+	Type:        tempest.USER_OPTION_TYPE,
+	Name:        "user",
+	Description: "The user",
+	Required:    true,
+}
+
+func (b *Bot) TicketBanAdd() tempest.Command {
+	return tempest.Command{
+		Name:                "add",
+		Description:         "Ban a user from opening tickets, replacing their current ban if any",
+		SlashCommandHandler: b.ticketBanAddImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{
+			ticketBanUserOption,
+			{
+				Type:        tempest.STRING_OPTION_TYPE,
+				Name:        "reason",
+				Description: "Why the user is banned. Only shown to helpers",
+				Required:    true,
+				MaxLength:   512,
+			},
+			{
+				Type:        tempest.NUMBER_OPTION_TYPE,
+				Name:        "days",
+				Description: "How many days the ban lasts. Permanent if not set",
+				MinValue:    0.01,
+			},
+		},
+	}
+}
+
+func (b *Bot) TicketBanRemove() tempest.Command {
+	return tempest.Command{
+		Name:                "remove",
+		Description:         "Let a banned user open tickets again",
+		SlashCommandHandler: b.ticketBanRemoveImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options:             []tempest.CommandOption{ticketBanUserOption},
+	}
+}
+
+func (b *Bot) TicketBanList() tempest.Command {
+	return tempest.Command{
+		Name:                "list",
+		Description:         "List the users banned from opening tickets",
+		SlashCommandHandler: b.ticketBanListImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+	}
+}
+
+// Return the user picked in the user option, replying with an error if it is missing
+func ticketBanUser(itx *tempest.CommandInteraction) (tempest.Snowflake, bool) {
+	userIDStr, err := utils.GetOption[string](itx, "user", true)
+	if err != nil {
+		return 0, false
+	}
+
+	userID, err := tempest.StringToSnowflake(userIDStr)
+	if err != nil {
+		itx.SendLinearReply("Invalid user ID", true)
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func (b *Bot) ticketBanAddImpl(itx *tempest.CommandInteraction) {
+	if itx.Member == nil || itx.Member.User == nil {
+		itx.SendLinearReply("Error: Unable to identify user", true)
+		return
+	}
+
+	userID, ok := ticketBanUser(itx)
+	if !ok {
+		return
+	}
+	reason, err := utils.GetOption[string](itx, "reason", true)
+	if err != nil {
+		return
+	}
+
+	ban := db.TicketBan{
+		UserID:   userID,
+		Reason:   reason,
+		BannedBy: itx.Member.User.ID,
+	}
+	if days, err := utils.GetOption[float64](itx, "days", false); err == nil {
+		ban.ExpiresAt = sql.NullTime{Time: time.Now().Add(time.Duration(days * float64(24*time.Hour))), Valid: true}
+	}
+
+	err = b.Store.BanUser(ban)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to save ticket ban", "banned_user_id", userID.String(), "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to ban a user from opening tickets",
+			Err:         err,
+			Interaction: itx.Interaction,
+		})
+		itx.SendLinearReply("Something went wrong while saving the ban.", true)
+		return
+	}
+	logging.For(itx.Interaction).Info("banned user from opening tickets", "banned_user_id", userID.String(), "expires", ban.ExpiresAt.Valid)
+
+	content := fmt.Sprintf("<@%d> can no longer open tickets.", userID)
+	if ban.ExpiresAt.Valid {
+		content = fmt.Sprintf("<@%d> can no longer open tickets until <t:%d:f>.", userID, ban.ExpiresAt.Time.Unix())
+	}
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         content,
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, true, nil)
+}
+
+func (b *Bot) ticketBanRemoveImpl(itx *tempest.CommandInteraction) {
+	userID, ok := ticketBanUser(itx)
+	if !ok {
+		return
+	}
+
+	found, err := b.Store.UnbanUser(userID)
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to remove ticket ban", "banned_user_id", userID.String(), "error", err)
+		b.alert(itx.Client, alerts.Alert{
+			Kind:        alerts.KIND_DATABASE,
+			Summary:     "Failed to lift a ban from opening tickets",
+			Err:         err,
+			Interaction: itx.Interaction,
+		})
+		itx.SendLinearReply("Something went wrong while removing the ban.", true)
+		return
+	}
+
+	content := fmt.Sprintf("<@%d> was not banned from opening tickets.", userID)
+	if found {
+		logging.For(itx.Interaction).Info("lifted ticket ban", "banned_user_id", userID.String())
+		content = fmt.Sprintf("<@%d> can open tickets again.", userID)
+	}
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         content,
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, true, nil)
+}
+
+func (b *Bot) ticketBanListImpl(itx *tempest.CommandInteraction) {
+	bans, err := b.Store.GetTicketBans()
+	if err != nil {
+		logging.For(itx.Interaction).Error("failed to fetch ticket bans", "error", err)
+		itx.SendLinearReply("Something went wrong while fetching the bans.", true)
+		return
+	}
+
+	// Expired bans are kept until they are removed, but no longer matter
+	now := time.Now()
+	var active []db.TicketBan
+	for _, ban := range bans {
+		if ban.Active(now) {
+			active = append(active, ban)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %d users banned from opening tickets\n", len(active))
+	for i, ban := range active {
+		line := fmt.Sprintf("- <@%d> by <@%d> <t:%d:R>, %s: %s\n",
+			ban.UserID, ban.BannedBy, ban.BannedAt.Unix(), describeBanExpiry(ban), responses.EscapeMarkdown(ban.Reason))
+
+		// Leave room for the line saying how many were left out
+		if utf8.RuneCountInString(sb.String())+utf8.RuneCountInString(line) > maxMessageLength-20 {
+			fmt.Fprintf(&sb, "…and %d more", len(active)-i)
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	itx.SendReply(tempest.ResponseMessageData{
+		Content:         sb.String(),
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, true, nil)
+}
+
+func describeBanExpiry(ban db.TicketBan) string {
+	if !ban.ExpiresAt.Valid {
+		return "permanent"
+	}
+
+	return fmt.Sprintf("ends <t:%d:R>", ban.ExpiresAt.Time.Unix())
+}
+
+// Return the message telling a banned user they can't open tickets, and when they can again
+func (b *Bot) ticketBannedMessage(locale tempest.Language, ban db.TicketBan) string {
+	message := b.Config.Tickets.BannedMessage
+	if message == "" {
+		message = i18n.Message(locale, i18n.TICKET_BANNED)
+	}
+	if ban.ExpiresAt.Valid {
+		message += "\n" + i18n.Message(locale, i18n.TICKET_BAN_ENDS, ban.ExpiresAt.Time.Unix())
+	}
+
+	return message
+}
@@ -0,0 +1,42 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/discordtest"
+
+	"github.com/amatsagu/tempest"
+)
+
+// The reasons of bans are shown as typed, without formatting or pings
+func TestTicketBanListEscapesReason(t *testing.T) {
+	b := newTestBot(t, nil)
+
+	err := b.client.RegisterCommand(TicketBanCommandGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.client.RegisterSubCommand(b.Command(b.TicketBanList()), TicketBanCommandGroup.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.store.BanUser(db.TicketBan{UserID: testUser.ID, Reason: "# spam <@201>", BannedBy: testHelper.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := b.send(discordtest.CommandInteraction(helperOrigin(testHelper, testTicketChannelID), TicketBanCommandGroup.Name,
+		tempest.CommandInteractionOption{Type: tempest.SUB_OPTION_TYPE, Name: "list"}))
+	if !strings.Contains(response.Data.Content, `: \# spam \<\@201\>`) {
+		t.Errorf("/ticket-ban list answered %q, want the reason escaped", response.Data.Content)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/transcript"
+	"github.com/pagefaultgames/ticketune/types"
+	"github.com/pagefaultgames/ticketune/utils"
+
+	"github.com/amatsagu/tempest"
+)
+
+func (b *Bot) TranscriptCommand() tempest.Command {
+	return tempest.Command{
+		Name:                "transcript",
+		Description:         "Get the transcript of a closed support ticket",
+		SlashCommandHandler: b.transcriptCommandImpl,
+		RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
+		Contexts:            []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
+		Options: []tempest.CommandOption{{
+			Type:        tempest.INTEGER_OPTION_TYPE,
+			Name:        "ticket",
+			Description: "The ticket number, as shown by /get-user-ticket",
+			Required:    true,
+			MinValue:    1,
+		}},
+	}
+}
+
+// Fetch every message of the ticket thread, render it as Markdown and HTML, post both files to the transcript channel
+// and record the posted message in the database.
+func (b *Bot) saveTranscript(logger *slog.Logger, client *tempest.BaseClient, channel types.Channel, ticket db.Ticket, closedBy tempest.Snowflake) error {
+	messages, err := utils.GetChannelMessages(client, channel.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch thread messages: %w", err)
+	}
+
+	info := transcript.Info{
+		TicketNumber: ticket.Number,
+		ThreadName:   channel.Name,
+		ThreadID:     channel.ID,
+		UserID:       ticket.UserID,
+		ClosedBy:     closedBy,
+		OpenedAt:     ticket.OpenedAt,
+		ClosedAt:     time.Now(),
+	}
+
+	html, err := transcript.HTML(info, messages)
+	if err != nil {
+		return fmt.Errorf("failed to render HTML transcript: %w", err)
+	}
+
+	baseName := fmt.Sprintf("ticket-%d", ticket.Number)
+	files := []tempest.File{
+		{Name: baseName + ".md", Reader: strings.NewReader(transcript.Markdown(info, messages))},
+		{Name: baseName + ".html", Reader: strings.NewReader(html)},
+	}
+
+	closer := "automatically"
+	if closedBy != 0 {
+		closer = fmt.Sprintf("by <@%d>", closedBy)
+	}
+
+	msg, err := utils.SendDiscordMessage(client, b.Config.Tickets.TranscriptChannelID, types.CreateMessageParams{
+		Content: fmt.Sprintf(
+			"Transcript of ticket #%d (%s), opened by <@%d> and closed %s. %d messages.",
+			ticket.Number, channel.Name, ticket.UserID, closer, len(messages),
+		),
+		// Don't ping anyone in the log channel
+		AllowedMentions: &tempest.AllowedMentions{},
+	}, files, false)
+	if err != nil {
+		return fmt.Errorf("failed to post transcript: %w", err)
+	}
+
+	err = b.Store.SaveTranscript(ticket.Number, b.Config.Tickets.TranscriptChannelID, msg.ID)
+	if err != nil {
+		// The transcript was posted, so the evidence is not lost even though we can't look it up by number
+		logger.Error("failed to save transcript reference to database", "error", err)
+	}
+
+	return nil
+}
+
+func (b *Bot) transcriptCommandImpl(itx *tempest.CommandInteraction) {
+	ticketNumber, err := utils.GetNumericOption[int64](itx, "ticket", true)
+	if err != nil {
+		return
+	}
+
+	logger := logging.WithTicket(itx.Interaction, ticketNumber)
+	channelID, messageID, err := b.Store.GetTranscript(ticketNumber)
+	if err == sql.ErrNoRows {
+		itx.SendLinearReply(fmt.Sprintf("I don't have a transcript for ticket #%d.", ticketNumber), true)
+		return
+	} else if err != nil {
+		logger.Error("failed to fetch transcript from database", "error", err)
+		itx.SendLinearReply("Something went wrong while looking up the transcript.", true)
+		return
+	}
+
+	messageLink := fmt.Sprintf("https://discord.com/channels/%d/%d/%d", itx.GuildID, channelID, messageID)
+
+	// Fetch the message again, as attachment URLs expire
+	msg, err := utils.GetChannelMessage(itx.Client, channelID, messageID)
+	if err != nil {
+		logger.Error("failed to fetch transcript message", "error", err)
+		itx.SendLinearReply(fmt.Sprintf("Transcript of ticket #%d: %s\nI couldn't fetch the files, the message may have been deleted.", ticketNumber, messageLink), true)
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Transcript of ticket #%d: %s", ticketNumber, messageLink)
+	for _, a := range msg.Attachments {
+		fmt.Fprintf(&sb, "\n- [%s](%s)", a.FileName, a.URL)
+	}
+
+	itx.SendLinearReply(sb.String(), true)
+}
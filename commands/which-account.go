@@ -1,29 +0,0 @@
-/*
- * SPDX-FileCopyrightText: 2025 Pagefault Games
- * SPDX-FileContributor: SirzBenjie
- * SPDX-FileContributor: patapancakes
- *
- * SPDX-License-Identifier: AGPL-3.0-or-later
- */
-
-package commands
-
-import (
-	utils "github.com/pagefaultgames/ticketune/utils"
-
-	"github.com/amatsagu/tempest"
-)
-
-const whichAccountCommandDescription = "Ping and ask the user which account they would like help with"
-const whichAccountMessage = "Which account would you like help with?"
-
-var WhichAccountCommand = tempest.Command{
-	Name:                "which-account",
-	Description:         whichAccountCommandDescription,
-	RequiredPermissions: tempest.ADMINISTRATOR_PERMISSION_FLAG,
-	Options:             []tempest.CommandOption{NO_PING_OPTION},
-	SlashCommandHandler: func(itx *tempest.CommandInteraction) {
-		utils.SayCommandTemplate(itx, whichAccountMessage, "The user has been asked which account they need help with.")
-	},
-	Contexts: []tempest.InteractionContextType{tempest.GUILD_CONTEXT_TYPE},
-}
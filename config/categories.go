@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Category is a kind of ticket users can open, with its own button on the ticket panel, channel and intake form
+type Category struct {
+	ID             string            `toml:"id"`              // Stored with the tickets of the category, so it must not change once tickets were opened
+	Name           string            `toml:"name"`            // Title of the category on the ticket panel
+	Description    string            `toml:"description"`     // Shown next to the button on the ticket panel
+	ButtonLabel    string            `toml:"button_label"`    // Label of the button opening a ticket. Defaults to "Open Ticket"
+	ChannelID      tempest.Snowflake `toml:"channel_id"`      // The channel the ticket threads are created in
+	HelperRoleID   tempest.Snowflake `toml:"helper_role_id"`  // The role pinged when a ticket is opened. Defaults to discord.helper_role_id
+	ThreadName     string            `toml:"thread_name"`     // Name of the ticket threads, where {username} is the user's username
+	WelcomeMessage string            `toml:"welcome_message"` // Posted in the ticket threads, where {user} mentions the user
+	Questions      []Question        `toml:"questions"`       // The intake form shown before opening a ticket. No form is shown without questions
+}
+
+// Question is a question of the intake form of a ticket category
+type Question struct {
+	ID          string `toml:"id"`          // Identifies the answer in the form
+	Label       string `toml:"label"`       // The question, which also labels the answer for helpers
+	Description string `toml:"description"` // Shown under the question, e.g. to give an example answer
+	Required    bool   `toml:"required"`
+	Paragraph   bool   `toml:"paragraph"`  // Whether the answer may span several lines
+	MaxLength   uint16 `toml:"max_length"` // 0 for Discord's limit of 4000 characters
+}
+
+// The category of the tickets opened before categories existed, and the only one if none are configured.
+// Unless they are configured, its welcome message and intake form are the built-in ones, translated to the user's
+// locale.
+const PASSWORD_CATEGORY = "password"
+
+// Discord's limits on the ticket panel and the intake form
+const (
+	MAX_CATEGORIES        = 10
+	MAX_QUESTIONS         = 5
+	maxLabelLength        = 45
+	maxDescriptionLength  = 100
+	maxAnswerLength       = 4000
+	maxButtonLabelLength  = 80
+	maxCategoryIDLength   = 40
+	maxCategoryNameLength = 100
+)
+
+const (
+	defaultButtonLabel        = "Open Ticket"
+	defaultThreadName         = "Ticket - {username}"
+	defaultPasswordThreadName = "Password Help - {username}"
+)
+
+// Category IDs end up in custom IDs, which are split on colons
+var categoryIDRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// TicketCategories returns the ticket categories, with the defaults of their missing settings filled in.
+// Without any configured, it returns only the password category, in tickets.channel_id.
+func (c *Config) TicketCategories() []Category {
+	categories := c.Categories
+	if len(categories) == 0 {
+		categories = []Category{{
+			ID:          PASSWORD_CATEGORY,
+			Name:        "Forgotten Password Support",
+			Description: "Forgot your password? Click the button to open a support ticket.",
+			ChannelID:   c.Tickets.ChannelID,
+		}}
+	}
+
+	filled := make([]Category, 0, len(categories))
+	for _, category := range categories {
+		if category.ButtonLabel == "" {
+			category.ButtonLabel = defaultButtonLabel
+		}
+		if category.HelperRoleID == 0 {
+			category.HelperRoleID = c.Discord.HelperRoleID
+		}
+		if category.ThreadName == "" {
+			category.ThreadName = defaultThreadName
+			if category.ID == PASSWORD_CATEGORY {
+				category.ThreadName = defaultPasswordThreadName
+			}
+		}
+		filled = append(filled, category)
+	}
+
+	return filled
+}
+
+// TicketCategory returns the ticket category with the given ID, if it exists
+func (c *Config) TicketCategory(id string) (Category, bool) {
+	for _, category := range c.TicketCategories() {
+		if category.ID == id {
+			return category, true
+		}
+	}
+
+	return Category{}, false
+}
+
+// Check the configured ticket categories
+func (c *Config) validateCategories() []error {
+	var errs []error
+
+	if len(c.Categories) > MAX_CATEGORIES {
+		errs = append(errs, fmt.Errorf("at most %d categories can be configured", MAX_CATEGORIES))
+	}
+
+	seen := map[string]bool{}
+	for i, category := range c.Categories {
+		name := fmt.Sprintf("categories[%d]", i)
+
+		switch {
+		case !categoryIDRegex.MatchString(category.ID) || len(category.ID) > maxCategoryIDLength:
+			errs = append(errs, fmt.Errorf("%s.id must be at most %d lowercase letters, digits, - or _, got %q", name, maxCategoryIDLength, category.ID))
+		case seen[category.ID]:
+			errs = append(errs, fmt.Errorf("%s.id %q is used by another category", name, category.ID))
+		}
+		seen[category.ID] = true
+
+		if category.Name == "" || utf8.RuneCountInString(category.Name) > maxCategoryNameLength {
+			errs = append(errs, fmt.Errorf("%s.name must be 1 to %d characters", name, maxCategoryNameLength))
+		}
+		if category.ChannelID == 0 {
+			errs = append(errs, fmt.Errorf("%s.channel_id is required", name))
+		}
+		if utf8.RuneCountInString(category.ButtonLabel) > maxButtonLabelLength {
+			errs = append(errs, fmt.Errorf("%s.button_label must be at most %d characters", name, maxButtonLabelLength))
+		}
+
+		if len(category.Questions) > MAX_QUESTIONS {
+			errs = append(errs, fmt.Errorf("%s can have at most %d questions", name, MAX_QUESTIONS))
+		}
+		questionIDs := map[string]bool{}
+		for j, q := range category.Questions {
+			qName := fmt.Sprintf("%s.questions[%d]", name, j)
+			if q.ID == "" || questionIDs[q.ID] {
+				errs = append(errs, fmt.Errorf("%s.id must be set, and unique in the category", qName))
+			}
+			questionIDs[q.ID] = true
+
+			if q.Label == "" || utf8.RuneCountInString(q.Label) > maxLabelLength {
+				errs = append(errs, fmt.Errorf("%s.label must be 1 to %d characters", qName, maxLabelLength))
+			}
+			if utf8.RuneCountInString(q.Description) > maxDescriptionLength {
+				errs = append(errs, fmt.Errorf("%s.description must be at most %d characters", qName, maxDescriptionLength))
+			}
+			if q.MaxLength > maxAnswerLength {
+				errs = append(errs, fmt.Errorf("%s.max_length must be at most %d", qName, maxAnswerLength))
+			}
+		}
+	}
+
+	if len(c.Categories) == 0 && c.Tickets.ChannelID == 0 {
+		errs = append(errs, errors.New("tickets.channel_id is required when no categories are configured"))
+	}
+
+	return errs
+}
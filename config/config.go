@@ -0,0 +1,287 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Configuration of the bot, loaded from a TOML file with environment variable overrides
+
+package config
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/amatsagu/tempest"
+)
+
+// Config is the whole configuration of the bot.
+// Each setting can be overridden by the environment variable named in its comment.
+type Config struct {
+	Discord         Discord         `toml:"discord"`
+	Tickets         Tickets         `toml:"tickets"`
+	Inactivity      Inactivity      `toml:"inactivity"`
+	Cooldown        Cooldown        `toml:"cooldown"`
+	Database        Database        `toml:"database"`
+	GitHub          GitHub          `toml:"github"`
+	CannedResponses CannedResponses `toml:"canned_responses"`
+	Logging         Logging         `toml:"logging"`
+
+	// The kinds of tickets users can open. Without any, users can only open password tickets in tickets.channel_id.
+	// TOML only
+	Categories []Category `toml:"categories"`
+
+	// The role needed to use each command, by command name ("group subcommand" for subcommands), overriding the
+	// bot's defaults. TICKETUNE_COMMAND_ROLES, as comma separated "command=role" pairs
+	CommandRoles map[string]string `toml:"command_roles"`
+}
+
+// Discord holds the bot's credentials and the guild it serves
+type Discord struct {
+	Token                    string            `toml:"token"`                      // DISCORD_BOT_TOKEN
+	PublicKey                string            `toml:"public_key"`                 // DISCORD_PUBLIC_KEY, hex encoded
+	ListeningAddress         string            `toml:"listening_address"`          // LISTENING_ADDRESS, where interactions are received
+	GuildID                  tempest.Snowflake `toml:"guild_id"`                   // DISCORD_GUILD_ID
+	HelperRoleID             tempest.Snowflake `toml:"helper_role_id"`             // HELPER_ROLE_ID
+	SeniorHelperRoleID       tempest.Snowflake `toml:"senior_helper_role_id"`      // SENIOR_HELPER_ROLE_ID, optional. If not set, helpers are senior helpers
+	TroubleshootingChannelID tempest.Snowflake `toml:"troubleshooting_channel_id"` // BOT_TROUBLESHOOTING_CHANNEL_ID
+}
+
+// Tickets holds where ticket threads and their transcripts are created
+type Tickets struct {
+	ChannelID           tempest.Snowflake `toml:"channel_id"`            // TICKET_CHANNEL_ID, the channel password tickets are created in when no categories are configured
+	SupportCategoryID   tempest.Snowflake `toml:"support_category_id"`   // SUPPORT_TICKET_CATEGORY_ID
+	TranscriptChannelID tempest.Snowflake `toml:"transcript_channel_id"` // TRANSCRIPT_CHANNEL_ID
+	BannedMessage       string            `toml:"banned_message"`        // TICKET_BANNED_MESSAGE, shown to users banned with /ticket-ban. Empty for a translated default
+}
+
+// Inactivity holds when tickets whose user stopped replying are reminded and closed
+type Inactivity struct {
+	ReminderDays float64 `toml:"reminder_days"` // INACTIVITY_REMINDER_DAYS, after the last helper message. 0 disables reminders and closing
+	CloseDays    float64 `toml:"close_days"`    // INACTIVITY_CLOSE_DAYS, after the reminder. 0 disables closing
+}
+
+// Cooldown holds how often tickets may be opened, so that helpers are not flooded with new tickets
+type Cooldown struct {
+	UserMinutes   float64 `toml:"user_minutes"`   // TICKET_COOLDOWN_MINUTES, after a user opened a ticket before they may open another. 0 disables
+	GlobalLimit   int     `toml:"global_limit"`   // TICKET_RATE_LIMIT, tickets everyone together may open every global_minutes, per bot instance. 0 disables
+	GlobalMinutes float64 `toml:"global_minutes"` // TICKET_RATE_LIMIT_MINUTES
+}
+
+// Database holds where tickets are stored
+type Database struct {
+	Driver string `toml:"driver"` // TICKETUNE_DB_DRIVER, one of the DRIVER_ constants
+	Path   string `toml:"path"`   // TICKETUNE_DB_FILE, the SQLite database file
+	URL    string `toml:"url"`    // TICKETUNE_DB_URL, the PostgreSQL connection string
+}
+
+// The databases tickets can be stored in
+const (
+	DRIVER_SQLITE   = "sqlite"   // A SQLite file, for a single bot
+	DRIVER_POSTGRES = "postgres" // A PostgreSQL database, which several bots can share
+	DRIVER_MEMORY   = "memory"   // In memory, lost when the bot stops
+)
+
+// The roles a command can require, see Config.CommandRoles. Each role may use the commands of the roles before it.
+const (
+	ROLE_EVERYONE      = "everyone"
+	ROLE_HELPER        = "helper"        // Members with discord.helper_role_id
+	ROLE_SENIOR_HELPER = "senior_helper" // Members with discord.senior_helper_role_id
+	ROLE_ADMIN         = "admin"         // Members with the Administrator permission
+)
+
+// Roles lists the roles a command can require, from the least to the most privileged
+var Roles = []string{ROLE_EVERYONE, ROLE_HELPER, ROLE_SENIOR_HELPER, ROLE_ADMIN}
+
+// GitHub holds the GitHub App used to open issues, and the repository they are opened in
+type GitHub struct {
+	PrivateKey     string `toml:"private_key"`      // TICKETUNE_GITHUB_BOT_PKEY, PEM encoded
+	PrivateKeyFile string `toml:"private_key_file"` // TICKETUNE_GITHUB_BOT_PKEY_FILE, read into PrivateKey if set
+	ClientID       string `toml:"client_id"`        // TICKETUNE_GITHUB_CLIENT_ID
+	InstallationID int64  `toml:"installation_id"`  // TICKETUNE_INSTALL_ID
+	Owner          string `toml:"owner"`            // TICKETUNE_GITHUB_OWNER
+	Repo           string `toml:"repo"`             // TICKETUNE_GITHUB_REPO
+}
+
+// CannedResponses holds where the canned responses are loaded from
+type CannedResponses struct {
+	File string `toml:"file"` // CANNED_RESPONSES_FILE
+}
+
+// Logging holds which log lines are written
+type Logging struct {
+	Level slog.Level `toml:"level"` // TICKETUNE_LOG_LEVEL, one of "debug", "info", "warn" or "error"
+}
+
+// ReminderAfter returns how long after the last helper message the user is reminded to reply
+func (i Inactivity) ReminderAfter() time.Duration {
+	return time.Duration(i.ReminderDays * float64(24*time.Hour))
+}
+
+// CloseAfter returns how long after the reminder, without a reply from the user, the ticket is closed
+func (i Inactivity) CloseAfter() time.Duration {
+	return time.Duration(i.CloseDays * float64(24*time.Hour))
+}
+
+// UserCooldown returns how long after opening a ticket a user must wait to open another
+func (c Cooldown) UserCooldown() time.Duration {
+	return time.Duration(c.UserMinutes * float64(time.Minute))
+}
+
+// GlobalInterval returns the interval in which at most GlobalLimit tickets may be opened
+func (c Cooldown) GlobalInterval() time.Duration {
+	return time.Duration(c.GlobalMinutes * float64(time.Minute))
+}
+
+// Default returns the configuration used for the settings missing from the file and the environment
+func Default() Config {
+	return Config{
+		Discord:         Discord{ListeningAddress: ":http"},
+		Inactivity:      Inactivity{ReminderDays: 3, CloseDays: 4},
+		Cooldown:        Cooldown{UserMinutes: 10, GlobalLimit: 30, GlobalMinutes: 10},
+		Database:        Database{Driver: DRIVER_SQLITE, Path: "ticketune-db.sqlite3"},
+		GitHub:          GitHub{Owner: "pagefaultgames", Repo: "pokerogue"},
+		CannedResponses: CannedResponses{File: "responses.json"},
+	}
+}
+
+// Load reads the configuration from the TOML file at `path` (if not empty), applies the overrides from the
+// environment variables found by `lookupEnv` (usually os.LookupEnv), and validates the result.
+// Every problem is reported at once, joined into a single error.
+func Load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	var errs []error
+
+	parsed := true
+	if path != "" {
+		meta, err := toml.DecodeFile(path, &cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s: %w", path, err))
+			parsed = false
+		} else {
+			for _, key := range meta.Undecoded() {
+				errs = append(errs, fmt.Errorf("%s: unknown setting %q", path, key.String()))
+			}
+		}
+	}
+
+	errs = append(errs, cfg.applyEnv(lookupEnv)...)
+
+	if cfg.GitHub.PrivateKeyFile != "" {
+		key, err := os.ReadFile(cfg.GitHub.PrivateKeyFile)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("github.private_key_file: %w", err))
+		}
+		cfg.GitHub.PrivateKey = string(key)
+	}
+
+	// The settings of a file that could not be parsed would all be reported as missing
+	if parsed {
+		errs = append(errs, cfg.Validate())
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that every required setting is present and well formed
+func (c *Config) Validate() error {
+	var errs []error
+
+	required := func(name string, missing bool) {
+		if missing {
+			errs = append(errs, fmt.Errorf("%s is required", name))
+		}
+	}
+
+	required("discord.token", c.Discord.Token == "")
+	required("discord.guild_id", c.Discord.GuildID == 0)
+	required("discord.helper_role_id", c.Discord.HelperRoleID == 0)
+	required("discord.troubleshooting_channel_id", c.Discord.TroubleshootingChannelID == 0)
+	required("tickets.support_category_id", c.Tickets.SupportCategoryID == 0)
+	required("tickets.transcript_channel_id", c.Tickets.TranscriptChannelID == 0)
+	required("github.client_id", c.GitHub.ClientID == "")
+	required("github.owner", c.GitHub.Owner == "")
+	required("github.repo", c.GitHub.Repo == "")
+	required("canned_responses.file", c.CannedResponses.File == "")
+
+	// Discord gives the public key as 32 hex encoded bytes
+	publicKey, err := hex.DecodeString(c.Discord.PublicKey)
+	if err != nil || len(publicKey) != 32 {
+		errs = append(errs, errors.New("discord.public_key must be the 64 hexadecimal characters shown in the Discord developer portal"))
+	}
+
+	switch c.Database.Driver {
+	case DRIVER_SQLITE:
+		required("database.path", c.Database.Path == "")
+	case DRIVER_POSTGRES:
+		required("database.url", c.Database.URL == "")
+	case DRIVER_MEMORY:
+	default:
+		errs = append(errs, fmt.Errorf("database.driver must be %q, %q or %q, got %q", DRIVER_SQLITE, DRIVER_POSTGRES, DRIVER_MEMORY, c.Database.Driver))
+	}
+
+	if c.GitHub.InstallationID <= 0 {
+		errs = append(errs, errors.New("github.installation_id must be a positive integer"))
+	}
+
+	if c.GitHub.PrivateKey == "" {
+		errs = append(errs, errors.New("github.private_key or github.private_key_file is required"))
+	} else if !isRSAPrivateKey(c.GitHub.PrivateKey) {
+		errs = append(errs, errors.New("github.private_key must be a PEM encoded RSA private key"))
+	}
+
+	errs = append(errs, c.validateCategories()...)
+
+	for command, role := range c.CommandRoles {
+		if !slices.Contains(Roles, role) {
+			errs = append(errs, fmt.Errorf("command_roles.%s must be one of %q, got %q", command, Roles, role))
+		}
+	}
+
+	if c.Inactivity.ReminderDays < 0 {
+		errs = append(errs, errors.New("inactivity.reminder_days must not be negative"))
+	}
+	if c.Inactivity.CloseDays < 0 {
+		errs = append(errs, errors.New("inactivity.close_days must not be negative"))
+	}
+	if c.Cooldown.UserMinutes < 0 {
+		errs = append(errs, errors.New("cooldown.user_minutes must not be negative"))
+	}
+	if c.Cooldown.GlobalLimit < 0 {
+		errs = append(errs, errors.New("cooldown.global_limit must not be negative"))
+	}
+	if c.Cooldown.GlobalLimit > 0 && c.Cooldown.GlobalMinutes <= 0 {
+		errs = append(errs, errors.New("cooldown.global_minutes must be positive when cooldown.global_limit is set"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Return whether `key` is a PEM encoded RSA private key, as GitHub Apps use
+func isRSAPrivateKey(key string) bool {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return false
+	}
+
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return true
+	}
+
+	_, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	return err == nil
+}
@@ -0,0 +1,51 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Return a lookupEnv for Load that finds the variables in `env`
+func testEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+// A file that can't be parsed is reported along with the environment variables that can't be
+func TestLoadReportsParseAndEnvErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticketune.toml")
+	err := os.WriteFile(path, []byte("[discord\ntoken = "), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(path, testEnv(map[string]string{"TICKET_RATE_LIMIT": "many"}))
+	if err == nil || !strings.Contains(err.Error(), "failed to parse") || !strings.Contains(err.Error(), "TICKET_RATE_LIMIT") {
+		t.Errorf("loading returned %v, want the parse error and the TICKET_RATE_LIMIT error", err)
+	}
+}
+
+// A variable that can't be parsed leaves its setting unchanged
+func TestApplyEnvKeepsSettingOnError(t *testing.T) {
+	cfg := Default()
+	cfg.GitHub.InstallationID = 42
+
+	errs := cfg.applyEnv(testEnv(map[string]string{"TICKET_RATE_LIMIT": "many", "TICKETUNE_INSTALL_ID": "abc"}))
+	if len(errs) != 2 {
+		t.Errorf("applying the environment returned %v, want 2 errors", errs)
+	}
+	if cfg.Cooldown.GlobalLimit != Default().Cooldown.GlobalLimit || cfg.GitHub.InstallationID != 42 {
+		t.Errorf("invalid variables changed the settings to %d and %d", cfg.Cooldown.GlobalLimit, cfg.GitHub.InstallationID)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Override the settings whose environment variable is set, returning the variables that could not be parsed
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) []error {
+	var errs []error
+
+	str := func(key string, dest *string) {
+		if value, ok := lookupEnv(key); ok {
+			*dest = value
+		}
+	}
+
+	snowflake := func(key string, dest *tempest.Snowflake) {
+		value, ok := lookupEnv(key)
+		if !ok {
+			return
+		}
+
+		id, err := tempest.StringToSnowflake(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a Discord ID, got %q", key, value))
+			return
+		}
+		*dest = id
+	}
+
+	number := func(unit string) func(key string, dest *float64) {
+		return func(key string, dest *float64) {
+			value, ok := lookupEnv(key)
+			if !ok {
+				return
+			}
+
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be a number of %s, got %q", key, unit, value))
+				return
+			}
+			*dest = n
+		}
+	}
+	days, minutes := number("days"), number("minutes")
+
+	str("DISCORD_BOT_TOKEN", &c.Discord.Token)
+	str("DISCORD_PUBLIC_KEY", &c.Discord.PublicKey)
+	str("LISTENING_ADDRESS", &c.Discord.ListeningAddress)
+	snowflake("DISCORD_GUILD_ID", &c.Discord.GuildID)
+	snowflake("HELPER_ROLE_ID", &c.Discord.HelperRoleID)
+	snowflake("SENIOR_HELPER_ROLE_ID", &c.Discord.SeniorHelperRoleID)
+	snowflake("BOT_TROUBLESHOOTING_CHANNEL_ID", &c.Discord.TroubleshootingChannelID)
+
+	snowflake("TICKET_CHANNEL_ID", &c.Tickets.ChannelID)
+	snowflake("SUPPORT_TICKET_CATEGORY_ID", &c.Tickets.SupportCategoryID)
+	snowflake("TRANSCRIPT_CHANNEL_ID", &c.Tickets.TranscriptChannelID)
+	str("TICKET_BANNED_MESSAGE", &c.Tickets.BannedMessage)
+
+	days("INACTIVITY_REMINDER_DAYS", &c.Inactivity.ReminderDays)
+	days("INACTIVITY_CLOSE_DAYS", &c.Inactivity.CloseDays)
+
+	minutes("TICKET_COOLDOWN_MINUTES", &c.Cooldown.UserMinutes)
+	if value, ok := lookupEnv("TICKET_RATE_LIMIT"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("TICKET_RATE_LIMIT must be an integer, got %q", value))
+		} else {
+			c.Cooldown.GlobalLimit = limit
+		}
+	}
+	minutes("TICKET_RATE_LIMIT_MINUTES", &c.Cooldown.GlobalMinutes)
+
+	str("TICKETUNE_DB_DRIVER", &c.Database.Driver)
+	str("TICKETUNE_DB_FILE", &c.Database.Path)
+	str("TICKETUNE_DB_URL", &c.Database.URL)
+
+	str("TICKETUNE_GITHUB_BOT_PKEY", &c.GitHub.PrivateKey)
+	str("TICKETUNE_GITHUB_BOT_PKEY_FILE", &c.GitHub.PrivateKeyFile)
+	str("TICKETUNE_GITHUB_CLIENT_ID", &c.GitHub.ClientID)
+	str("TICKETUNE_GITHUB_OWNER", &c.GitHub.Owner)
+	str("TICKETUNE_GITHUB_REPO", &c.GitHub.Repo)
+	if value, ok := lookupEnv("TICKETUNE_INSTALL_ID"); ok {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("TICKETUNE_INSTALL_ID must be an integer, got %q", value))
+		} else {
+			c.GitHub.InstallationID = id
+		}
+	}
+
+	str("CANNED_RESPONSES_FILE", &c.CannedResponses.File)
+
+	if value, ok := lookupEnv("TICKETUNE_COMMAND_ROLES"); ok {
+		if c.CommandRoles == nil {
+			c.CommandRoles = map[string]string{}
+		}
+		for pair := range strings.SplitSeq(value, ",") {
+			command, role, found := strings.Cut(pair, "=")
+			if !found {
+				errs = append(errs, fmt.Errorf("TICKETUNE_COMMAND_ROLES must be comma separated command=role pairs, got %q", pair))
+				continue
+			}
+			c.CommandRoles[strings.TrimSpace(command)] = strings.TrimSpace(role)
+		}
+	}
+
+	if value, ok := lookupEnv("TICKETUNE_LOG_LEVEL"); ok {
+		err := c.Logging.Level.UnmarshalText([]byte(value))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("TICKETUNE_LOG_LEVEL must be debug, info, warn or error, got %q", value))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,111 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+// CannedResponseRevision is one saved version of a canned response edited with /response
+type CannedResponseRevision struct {
+	Name      string            // The name of the canned response
+	Revision  int64             // 1 for the first version of the name, incremented with every edit
+	Data      string            // The canned response as JSON, or empty if this revision deleted it
+	AuthorID  tempest.Snowflake // The helper who saved the revision
+	CreatedAt time.Time         // When the revision was saved
+}
+
+// Deleted returns whether the revision deleted the canned response
+func (r CannedResponseRevision) Deleted() bool {
+	return r.Data == ""
+}
+
+const cannedResponseRevisionColumns = `name, revision, COALESCE(data, ''), author_id, created_at`
+
+// Scan a row selected with cannedResponseRevisionColumns into a CannedResponseRevision
+func scanCannedResponseRevision(row interface{ Scan(...any) error }) (CannedResponseRevision, error) {
+	var r CannedResponseRevision
+	err := row.Scan(&r.Name, &r.Revision, &r.Data, &r.AuthorID, &r.CreatedAt)
+	return r, err
+}
+
+// How many times saving a revision is tried, as concurrent edits of a canned response may pick the same revision number
+const saveRevisionAttempts = 3
+
+// SaveCannedResponseRevision records a new version of a canned response and returns its revision number.
+// An empty `data` records that the response was deleted.
+func (d *DB) SaveCannedResponseRevision(name string, data string, authorID tempest.Snowflake) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		var revision int64
+		err := d.db.QueryRow(
+			d.bind(`INSERT INTO canned_response_revisions (name, revision, data, author_id)
+			VALUES (?, (SELECT COALESCE(MAX(revision), 0) + 1 FROM canned_response_revisions WHERE name = ?), ?, ?)
+			RETURNING revision`),
+			name,
+			name,
+			nullableString(data),
+			authorID,
+		).Scan(&revision)
+
+		// Another bot saved the same revision number first, so the next attempt picks the one after it
+		if err != nil && attempt < saveRevisionAttempts && d.dialect.isUniqueViolation(err) {
+			continue
+		}
+
+		return revision, err
+	}
+}
+
+// GetCannedResponses returns the latest revision of every canned response saved with /response, including deletions.
+func (d *DB) GetCannedResponses() ([]CannedResponseRevision, error) {
+	rows, err := d.db.Query(
+		`SELECT ` + cannedResponseRevisionColumns + ` FROM canned_response_revisions r
+		WHERE revision = (SELECT MAX(revision) FROM canned_response_revisions WHERE name = r.name)
+		ORDER BY name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []CannedResponseRevision
+	for rows.Next() {
+		r, err := scanCannedResponseRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetCannedResponseHistory returns every revision of a canned response, newest first.
+func (d *DB) GetCannedResponseHistory(name string) ([]CannedResponseRevision, error) {
+	rows, err := d.db.Query(
+		d.bind(`SELECT `+cannedResponseRevisionColumns+` FROM canned_response_revisions WHERE name = ? ORDER BY revision DESC`),
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []CannedResponseRevision
+	for rows.Next() {
+		r, err := scanCannedResponseRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, rows.Err()
+}
@@ -9,75 +9,131 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/amatsagu/tempest"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-const dbFile = "ticketune-db.sqlite3"
-
-// DB wraps the sql.DB for ticketune
+// DB is the Store kept in a SQL database, either SQLite or PostgreSQL
 type DB struct {
-	db *sql.DB // The underlying database connection
+	db      *sql.DB // The underlying database connection
+	dialect dialect // The SQL database behind db
 }
 
-var TicketuneDB *DB // Global instance of the DB
+// OpenSQLite opens (or creates) the SQLite database at `path` and brings its schema up to date
+func OpenSQLite(path string) (*DB, error) {
+	return open("sqlite3", path, sqliteDialect)
+}
 
-func Init() error {
-	var err error
-	TicketuneDB, err = open()
+// OpenPostgres connects to the PostgreSQL database at `url` and brings its schema up to date.
+// Several bots may share the database.
+func OpenPostgres(url string) (*DB, error) {
+	return open("pgx", url, postgresDialect)
+}
+
+// Open a database with the given database/sql driver, and migrate it
+func open(driver string, source string, dialect dialect) (*DB, error) {
+	db, err := sql.Open(driver, source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = TicketuneDB.db.Ping()
+	err = db.Ping()
 	if err != nil {
-		return err
+		db.Close()
+		return nil, err
 	}
 
-	return nil
+	d := &DB{db: db, dialect: dialect}
+	err = d.Migrate()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return d, nil
 }
 
-func Get() *DB {
-	return TicketuneDB
+// Rewrite the placeholders of `query` for the database, see dialect.bind
+func (d *DB) bind(query string) string {
+	return d.dialect.bind(query)
 }
 
-// Open (or or create) the ticketune database and ensure the support_tickets table exists
-func open() (*DB, error) {
-	db, err := sql.Open("sqlite3", dbFile)
+// TicketStatus is the value of the status column of a ticket
+type TicketStatus string
+
+const (
+	TICKET_OPEN   TicketStatus = "open"
+	TICKET_CLOSED TicketStatus = "closed"
+)
+
+// Ticket is a single row of the tickets table
+type Ticket struct {
+	Number   int64             // Auto-incremented ticket number
+	UserID   tempest.Snowflake // The user who opened the ticket
+	ThreadID tempest.Snowflake // The thread created for the ticket
+	Status   TicketStatus      // Whether the ticket is open or closed
+	OpenedAt time.Time         // When the ticket was opened
+	ClosedAt sql.NullTime      // When the ticket was closed, if it is closed
+	ClosedBy tempest.Snowflake // The helper who closed the ticket, or 0 if it is open or was closed by the bot
+	Assignee tempest.Snowflake // The helper working on the ticket, or 0 if nobody has claimed it
+	Locale   tempest.Language  // The user's Discord locale when they opened the ticket
+	Category string            // The ID of the ticket's category, see config.Category
+}
+
+const ticketColumns = `ticket_number, user_id, thread_id, status, opened_at, closed_at, COALESCE(closed_by, 0), COALESCE(assigned_to, 0), locale, category`
+
+// Scan a row selected with ticketColumns into a Ticket
+func scanTicket(row interface{ Scan(...any) error }) (Ticket, error) {
+	var t Ticket
+	err := row.Scan(&t.Number, &t.UserID, &t.ThreadID, &t.Status, &t.OpenedAt, &t.ClosedAt, &t.ClosedBy, &t.Assignee, &t.Locale, &t.Category)
+	return t, err
+}
+
+// OpenTicket records a new open ticket for a user in a category, in the user's locale, and returns its ticket number.
+// Any ticket still marked open for the user in the category (e.g. because its thread was deleted or locked by hand)
+// is closed first.
+func (d *DB) OpenTicket(userID tempest.Snowflake, category string, threadID tempest.Snowflake, locale tempest.Language) (int64, error) {
+	tx, err := d.db.Begin()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	defer tx.Rollback()
 
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS support_tickets (
-	       user_id TEXT PRIMARY KEY,
-	       thread_id TEXT NOT NULL,
-	       created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
-       );`)
+	_, err = tx.Exec(
+		d.bind(`UPDATE tickets SET status = 'closed', closed_at = CURRENT_TIMESTAMP WHERE user_id = ? AND category = ? AND status = 'open'`),
+		userID,
+		category,
+	)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &DB{db: db}, nil
-}
-
-// SetUserThread stores or updates a user's thread info.
-func (d *DB) SetUserThread(userID tempest.Snowflake, threadID tempest.Snowflake) error {
-	_, err := d.db.Exec(
-		`INSERT OR REPLACE INTO support_tickets (user_id, thread_id) VALUES (?, ?)`,
+	var number int64
+	err = tx.QueryRow(
+		d.bind(`INSERT INTO tickets (user_id, thread_id, locale, category) VALUES (?, ?, ?, ?) RETURNING ticket_number`),
 		userID,
 		threadID,
-	)
+		locale,
+		category,
+	).Scan(&number)
+	if err != nil {
+		return 0, err
+	}
 
-	return err
+	return number, tx.Commit()
 }
 
-// GetUserThread returns the thread ID and creation time for a user, or empty string and zero time if not found.
-func (d *DB) GetUserThread(userID tempest.Snowflake) (tempest.Snowflake, error) {
+// GetUserThread returns the thread ID of the user's open ticket in a category, or sql.ErrNoRows if they have none.
+func (d *DB) GetUserThread(userID tempest.Snowflake, category string) (tempest.Snowflake, error) {
 	row := d.db.QueryRow(
-		`SELECT thread_id FROM support_tickets WHERE user_id = ?`,
+		d.bind(`SELECT thread_id FROM tickets WHERE user_id = ? AND category = ? AND status = 'open'`),
 		userID,
+		category,
 	)
 
 	var threadID tempest.Snowflake
@@ -89,10 +145,10 @@ func (d *DB) GetUserThread(userID tempest.Snowflake) (tempest.Snowflake, error)
 	return threadID, nil
 }
 
-// GetThreadUser returns the user ID associated with a thread ID.
+// GetThreadUser returns the user ID associated with the open ticket in a thread.
 func (d *DB) GetThreadUser(threadID tempest.Snowflake) (tempest.Snowflake, error) {
 	row := d.db.QueryRow(
-		`SELECT user_id FROM support_tickets WHERE thread_id = ?`,
+		d.bind(`SELECT user_id FROM tickets WHERE thread_id = ? AND status = 'open'`),
 		threadID,
 	)
 
@@ -105,9 +161,35 @@ func (d *DB) GetThreadUser(threadID tempest.Snowflake) (tempest.Snowflake, error
 	return userID, nil
 }
 
-// DeleteUserThread removes a user's thread record.
-func (d *DB) DeleteUserThread(userID string) error {
-	_, err := d.db.Exec(`DELETE FROM support_tickets WHERE user_id = ?`, userID)
+// GetUserTickets returns every ticket (open or closed) opened by a user, newest first.
+func (d *DB) GetUserTickets(userID tempest.Snowflake) ([]Ticket, error) {
+	rows, err := d.db.Query(
+		d.bind(`SELECT `+ticketColumns+` FROM tickets WHERE user_id = ? ORDER BY ticket_number DESC`),
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []Ticket
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, t)
+	}
+
+	return tickets, rows.Err()
+}
+
+// CloseUserTicket marks a user's open tickets as closed, in every category, without recording a closing helper.
+func (d *DB) CloseUserTicket(userID tempest.Snowflake) error {
+	_, err := d.db.Exec(
+		d.bind(`UPDATE tickets SET status = 'closed', closed_at = CURRENT_TIMESTAMP WHERE user_id = ? AND status = 'open'`),
+		userID,
+	)
 	if err != nil {
 		return err
 	}
@@ -115,25 +197,46 @@ func (d *DB) DeleteUserThread(userID string) error {
 	return nil
 }
 
-// Close closes the database connection.
-func (d *DB) Close() error {
-	return d.db.Close()
+// Ping checks that the database can be reached.
+func (d *DB) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
 }
 
-// Cleanup threads older than 1 month.
-// Unused in favor of explicit thread closing, but kept for potential future use.
-func (d *DB) CleanupOldThreads() error {
-	_, err := d.db.Exec(`DELETE FROM support_tickets WHERE created_at < datetime('now', '-1 month')`)
+// TryLockJob takes the lock of a background job, held by a transaction until unlock rolls it back.
+// The lock is released as well if the connection is lost, so a bot that died can't hold it forever.
+func (d *DB) TryLockJob(ctx context.Context, job string) (func(), bool, error) {
+	if d.dialect.tryLockJob == "" {
+		return func() {}, true, nil
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
-	return nil
+	var ok bool
+	err = tx.QueryRowContext(ctx, d.bind(d.dialect.tryLockJob), job).Scan(&ok)
+	if err != nil || !ok {
+		tx.Rollback()
+		return nil, false, err
+	}
+
+	return func() { tx.Rollback() }, true, nil
+}
+
+// Close closes the database connection.
+func (d *DB) Close() error {
+	return d.db.Close()
 }
 
-// Delete a thread record by thread ID, and return the user ID that was associated with it.
-func (d *DB) CloseThread(threadId tempest.Snowflake) (tempest.Snowflake, error) {
-	row := d.db.QueryRow(`DELETE FROM support_tickets WHERE thread_id = ? RETURNING user_id`, threadId)
+// Mark the open ticket in a thread as closed by `closedBy` (0 for the bot), and return the user ID that was associated with it.
+func (d *DB) CloseThread(threadId tempest.Snowflake, closedBy tempest.Snowflake) (tempest.Snowflake, error) {
+	row := d.db.QueryRow(
+		d.bind(`UPDATE tickets SET status = 'closed', closed_at = CURRENT_TIMESTAMP, closed_by = ?
+		WHERE thread_id = ? AND status = 'open' RETURNING user_id`),
+		nullableID(closedBy),
+		threadId,
+	)
 
 	var userID tempest.Snowflake
 	err := row.Scan(&userID)
@@ -143,3 +246,162 @@ func (d *DB) CloseThread(threadId tempest.Snowflake) (tempest.Snowflake, error)
 
 	return userID, nil
 }
+
+// GetThreadTicket returns the open ticket associated with a thread ID.
+func (d *DB) GetThreadTicket(threadID tempest.Snowflake) (Ticket, error) {
+	row := d.db.QueryRow(
+		d.bind(`SELECT `+ticketColumns+` FROM tickets WHERE thread_id = ? AND status = 'open'`),
+		threadID,
+	)
+
+	return scanTicket(row)
+}
+
+// SaveTranscript records the message a ticket's transcript was posted in.
+func (d *DB) SaveTranscript(ticketNumber int64, channelID tempest.Snowflake, messageID tempest.Snowflake) error {
+	_, err := d.db.Exec(
+		d.bind(`INSERT INTO transcripts (ticket_number, channel_id, message_id) VALUES (?, ?, ?)
+		ON CONFLICT (ticket_number) DO UPDATE
+		SET channel_id = excluded.channel_id, message_id = excluded.message_id, created_at = CURRENT_TIMESTAMP`),
+		ticketNumber,
+		channelID,
+		messageID,
+	)
+
+	return err
+}
+
+// GetTranscript returns the channel and message ID a ticket's transcript was posted in.
+func (d *DB) GetTranscript(ticketNumber int64) (tempest.Snowflake, tempest.Snowflake, error) {
+	row := d.db.QueryRow(
+		d.bind(`SELECT channel_id, message_id FROM transcripts WHERE ticket_number = ?`),
+		ticketNumber,
+	)
+
+	var channelID, messageID tempest.Snowflake
+	err := row.Scan(&channelID, &messageID)
+	if err != nil {
+		return tempest.Snowflake(0), tempest.Snowflake(0), err
+	}
+
+	return channelID, messageID, nil
+}
+
+// SetTicketAssignee records `helperID` as the helper working on the open ticket in a thread.
+// Passing 0 as `helperID` unassigns the ticket. Returns the updated ticket.
+func (d *DB) SetTicketAssignee(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	row := d.db.QueryRow(
+		d.bind(`UPDATE tickets SET assigned_to = ?, assigned_at = CURRENT_TIMESTAMP
+		WHERE thread_id = ? AND status = 'open' RETURNING `+ticketColumns),
+		nullableID(helperID),
+		threadID,
+	)
+
+	return scanTicket(row)
+}
+
+// ClaimTicket records `helperID` as the helper working on the open ticket in a thread, if nobody is.
+// The check is part of the update, so that two helpers claiming the ticket at once can't both succeed.
+func (d *DB) ClaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	row := d.db.QueryRow(
+		d.bind(`UPDATE tickets SET assigned_to = ?, assigned_at = CURRENT_TIMESTAMP
+		WHERE thread_id = ? AND status = 'open' AND assigned_to IS NULL RETURNING `+ticketColumns),
+		helperID,
+		threadID,
+	)
+
+	return d.checkAssigneeUpdate(threadID, row)
+}
+
+// UnclaimTicket unassigns the open ticket in a thread, if `helperID` is still the helper working on it
+func (d *DB) UnclaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	row := d.db.QueryRow(
+		d.bind(`UPDATE tickets SET assigned_to = NULL, assigned_at = CURRENT_TIMESTAMP
+		WHERE thread_id = ? AND status = 'open' AND assigned_to = ? RETURNING `+ticketColumns),
+		threadID,
+		helperID,
+	)
+
+	return d.checkAssigneeUpdate(threadID, row)
+}
+
+// Scan the ticket updated by a conditional change of assignee.
+// When nothing was updated, tell a missing ticket (sql.ErrNoRows) from a changed assignee (ErrTicketClaimed).
+func (d *DB) checkAssigneeUpdate(threadID tempest.Snowflake, row *sql.Row) (Ticket, error) {
+	ticket, err := scanTicket(row)
+	if err != sql.ErrNoRows {
+		return ticket, err
+	}
+
+	ticket, err = d.GetThreadTicket(threadID)
+	if err != nil {
+		return ticket, err
+	}
+
+	return ticket, ErrTicketClaimed
+}
+
+// GetOpenTickets returns every open ticket, oldest first.
+func (d *DB) GetOpenTickets() ([]Ticket, error) {
+	rows, err := d.db.Query(`SELECT ` + ticketColumns + ` FROM tickets WHERE status = 'open' ORDER BY opened_at, ticket_number`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []Ticket
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, t)
+	}
+
+	return tickets, rows.Err()
+}
+
+// TicketEventKind is the value of the kind column of a ticket event
+type TicketEventKind string
+
+const (
+	EVENT_INACTIVITY_REMINDER TicketEventKind = "inactivity_reminder" // The user was reminded to reply
+	EVENT_AUTO_CLOSED         TicketEventKind = "auto_closed"         // The ticket was closed for inactivity
+	EVENT_AUTO_CLOSE_FAILED   TicketEventKind = "auto_close_failed"   // Closing the ticket for inactivity failed
+)
+
+// TicketEvent is a single row of the ticket_events table
+type TicketEvent struct {
+	TicketNumber int64             // The ticket the event happened to
+	Kind         TicketEventKind   // What happened
+	MessageID    tempest.Snowflake // The message the bot sent for the event, if any
+	Details      string            // Free-form details, such as an error message
+	CreatedAt    time.Time         // When the event happened
+}
+
+// LogTicketEvent records an action the bot took on a ticket. `messageID` may be 0.
+func (d *DB) LogTicketEvent(ticketNumber int64, kind TicketEventKind, messageID tempest.Snowflake, details string) error {
+	_, err := d.db.Exec(
+		d.bind(`INSERT INTO ticket_events (ticket_number, kind, message_id, details) VALUES (?, ?, ?, ?)`),
+		ticketNumber,
+		kind,
+		nullableID(messageID),
+		details,
+	)
+
+	return err
+}
+
+// GetLastTicketEvent returns the most recent event of the given kind for a ticket, or sql.ErrNoRows if there is none.
+func (d *DB) GetLastTicketEvent(ticketNumber int64, kind TicketEventKind) (TicketEvent, error) {
+	row := d.db.QueryRow(
+		d.bind(`SELECT ticket_number, kind, COALESCE(message_id, 0), details, created_at FROM ticket_events
+		WHERE ticket_number = ? AND kind = ? ORDER BY id DESC LIMIT 1`),
+		ticketNumber,
+		kind,
+	)
+
+	var e TicketEvent
+	err := row.Scan(&e.TicketNumber, &e.Kind, &e.MessageID, &e.Details, &e.CreatedAt)
+	return e, err
+}
@@ -0,0 +1,103 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/amatsagu/tempest"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// dialect holds what differs between the SQL databases DB supports.
+// Queries are written once, in the SQL both databases understand, with ? placeholders.
+type dialect struct {
+	name                string // The name of the database, for logging
+	migrations          string // The directory of migrationFiles holding the database's migrations
+	numberedParams      bool   // Whether placeholders are written $1, $2... instead of ?
+	lockMigrations      string // Statement run first in every migration, so that bots starting together migrate one at a time
+	tryLockJob          string // Query taking the lock of a background job for the transaction, returning whether it was free. Empty if only one bot uses the database
+	createSchemaVersion string // Statement creating where the schema version is stored, if it needs creating
+	schemaVersion       string // Query returning the number of the last applied migration
+	setSchemaVersion    string // Format of the statement recording that a migration was applied
+
+	isUniqueViolation func(err error) bool // Whether `err` is from a row breaking a primary key or unique constraint
+}
+
+var sqliteDialect = dialect{
+	name:             "SQLite",
+	migrations:       "migrations/sqlite",
+	schemaVersion:    `PRAGMA user_version`,
+	setSchemaVersion: `PRAGMA user_version = %d`,
+	isUniqueViolation: func(err error) bool {
+		var sqliteErr sqlite3.Error
+		return errors.As(err, &sqliteErr) &&
+			(sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique)
+	},
+}
+
+var postgresDialect = dialect{
+	name:           "PostgreSQL",
+	migrations:     "migrations/postgres",
+	numberedParams: true,
+	// The key is arbitrary, it only has to be the same for every bot
+	lockMigrations: `SELECT pg_advisory_xact_lock(8425513)`,
+	tryLockJob:     `SELECT pg_try_advisory_xact_lock(8425513, hashtext(?))`,
+	createSchemaVersion: `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+	)`,
+	schemaVersion:    `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`,
+	setSchemaVersion: `INSERT INTO schema_migrations (version) VALUES (%d)`,
+	isUniqueViolation: func(err error) bool {
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == "23505" // unique_violation
+	},
+}
+
+// Rewrite the ? placeholders of `query` in the dialect's syntax
+func (d dialect) bind(query string) string {
+	if !d.numberedParams {
+		return query
+	}
+
+	var sb strings.Builder
+	param := 0
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+
+		param++
+		sb.WriteString("$" + strconv.Itoa(param))
+	}
+
+	return sb.String()
+}
+
+// Return `id` as a query argument, or NULL if it is 0
+func nullableID(id tempest.Snowflake) any {
+	if id == 0 {
+		return nil
+	}
+
+	return id
+}
+
+// Return `s` as a query argument, or NULL if it is empty
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
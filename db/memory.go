@@ -0,0 +1,384 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+// transcript is where a ticket's transcript was posted
+type transcript struct {
+	channelID tempest.Snowflake
+	messageID tempest.Snowflake
+}
+
+// MemoryStore is a Store that keeps everything in memory, for tests and trying the bot out.
+// Everything is lost when the bot stops.
+type MemoryStore struct {
+	mu          sync.Mutex
+	tickets     []Ticket // Every ticket, by ascending ticket number
+	transcripts map[int64]transcript
+	intakes     map[int64][]IntakeAnswer // The intake form answers, by ticket number
+	events      []TicketEvent            // Every ticket event, oldest first
+	revisions   []CannedResponseRevision // Every canned response revision, oldest first
+	bans        map[tempest.Snowflake]TicketBan
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		transcripts: map[int64]transcript{},
+		intakes:     map[int64][]IntakeAnswer{},
+		bans:        map[tempest.Snowflake]TicketBan{},
+	}
+}
+
+// Return the index of the first ticket matching `match`, or -1 if there is none. The lock must be held.
+func (m *MemoryStore) find(match func(t Ticket) bool) int {
+	return slices.IndexFunc(m.tickets, match)
+}
+
+// Return the index of the user's open ticket in a category, or -1
+func (m *MemoryStore) findUserTicket(userID tempest.Snowflake, category string) int {
+	return m.find(func(t Ticket) bool { return t.UserID == userID && t.Category == category && t.Status == TICKET_OPEN })
+}
+
+// Return the index of the open ticket in a thread, or -1
+func (m *MemoryStore) findThreadTicket(threadID tempest.Snowflake) int {
+	return m.find(func(t Ticket) bool { return t.ThreadID == threadID && t.Status == TICKET_OPEN })
+}
+
+// Mark the ticket at index `i` as closed by `closedBy`. The lock must be held.
+func (m *MemoryStore) close(i int, closedBy tempest.Snowflake) {
+	m.tickets[i].Status = TICKET_CLOSED
+	m.tickets[i].ClosedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.tickets[i].ClosedBy = closedBy
+}
+
+func (m *MemoryStore) OpenTicket(userID tempest.Snowflake, category string, threadID tempest.Snowflake, locale tempest.Language) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.findUserTicket(userID, category); i != -1 {
+		m.close(i, 0)
+	}
+
+	number := int64(len(m.tickets) + 1)
+	m.tickets = append(m.tickets, Ticket{
+		Number:   number,
+		UserID:   userID,
+		ThreadID: threadID,
+		Status:   TICKET_OPEN,
+		OpenedAt: time.Now().UTC(),
+		Locale:   locale,
+		Category: category,
+	})
+
+	return number, nil
+}
+
+func (m *MemoryStore) GetUserThread(userID tempest.Snowflake, category string) (tempest.Snowflake, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findUserTicket(userID, category)
+	if i == -1 {
+		return tempest.Snowflake(0), sql.ErrNoRows
+	}
+
+	return m.tickets[i].ThreadID, nil
+}
+
+func (m *MemoryStore) GetThreadUser(threadID tempest.Snowflake) (tempest.Snowflake, error) {
+	ticket, err := m.GetThreadTicket(threadID)
+	return ticket.UserID, err
+}
+
+func (m *MemoryStore) GetUserTickets(userID tempest.Snowflake) ([]Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var tickets []Ticket
+	for i := len(m.tickets) - 1; i >= 0; i-- {
+		if m.tickets[i].UserID == userID {
+			tickets = append(tickets, m.tickets[i])
+		}
+	}
+
+	return tickets, nil
+}
+
+func (m *MemoryStore) GetThreadTicket(threadID tempest.Snowflake) (Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findThreadTicket(threadID)
+	if i == -1 {
+		return Ticket{}, sql.ErrNoRows
+	}
+
+	return m.tickets[i], nil
+}
+
+func (m *MemoryStore) GetOpenTickets() ([]Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var tickets []Ticket
+	for _, t := range m.tickets {
+		if t.Status == TICKET_OPEN {
+			tickets = append(tickets, t)
+		}
+	}
+
+	return tickets, nil
+}
+
+func (m *MemoryStore) CloseUserTicket(userID tempest.Snowflake) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, t := range m.tickets {
+		if t.UserID == userID && t.Status == TICKET_OPEN {
+			m.close(i, 0)
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) CloseThread(threadID tempest.Snowflake, closedBy tempest.Snowflake) (tempest.Snowflake, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findThreadTicket(threadID)
+	if i == -1 {
+		return tempest.Snowflake(0), sql.ErrNoRows
+	}
+
+	m.close(i, closedBy)
+	return m.tickets[i].UserID, nil
+}
+
+func (m *MemoryStore) SetTicketAssignee(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findThreadTicket(threadID)
+	if i == -1 {
+		return Ticket{}, sql.ErrNoRows
+	}
+
+	m.tickets[i].Assignee = helperID
+	return m.tickets[i], nil
+}
+
+func (m *MemoryStore) ClaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	return m.setTicketAssigneeIf(threadID, 0, helperID)
+}
+
+func (m *MemoryStore) UnclaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	return m.setTicketAssigneeIf(threadID, helperID, 0)
+}
+
+// Assign the open ticket in a thread to `helperID` if it is assigned to `current`, or return ErrTicketClaimed
+func (m *MemoryStore) setTicketAssigneeIf(threadID tempest.Snowflake, current tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findThreadTicket(threadID)
+	if i == -1 {
+		return Ticket{}, sql.ErrNoRows
+	}
+	if m.tickets[i].Assignee != current {
+		return m.tickets[i], ErrTicketClaimed
+	}
+
+	m.tickets[i].Assignee = helperID
+	return m.tickets[i], nil
+}
+
+func (m *MemoryStore) SaveTranscript(ticketNumber int64, channelID tempest.Snowflake, messageID tempest.Snowflake) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.transcripts[ticketNumber] = transcript{channelID: channelID, messageID: messageID}
+	return nil
+}
+
+func (m *MemoryStore) GetTranscript(ticketNumber int64) (tempest.Snowflake, tempest.Snowflake, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.transcripts[ticketNumber]
+	if !ok {
+		return tempest.Snowflake(0), tempest.Snowflake(0), sql.ErrNoRows
+	}
+
+	return t.channelID, t.messageID, nil
+}
+
+func (m *MemoryStore) SaveTicketIntake(ticketNumber int64, answers []IntakeAnswer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.intakes[ticketNumber] = slices.Clone(answers)
+	return nil
+}
+
+func (m *MemoryStore) GetTicketIntake(ticketNumber int64) ([]IntakeAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.intakes[ticketNumber]), nil
+}
+
+func (m *MemoryStore) LogTicketEvent(ticketNumber int64, kind TicketEventKind, messageID tempest.Snowflake, details string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, TicketEvent{
+		TicketNumber: ticketNumber,
+		Kind:         kind,
+		MessageID:    messageID,
+		Details:      details,
+		CreatedAt:    time.Now().UTC(),
+	})
+
+	return nil
+}
+
+func (m *MemoryStore) GetLastTicketEvent(ticketNumber int64, kind TicketEventKind) (TicketEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if m.events[i].TicketNumber == ticketNumber && m.events[i].Kind == kind {
+			return m.events[i], nil
+		}
+	}
+
+	return TicketEvent{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) SaveCannedResponseRevision(name string, data string, authorID tempest.Snowflake) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var revision int64 = 1
+	for _, r := range m.revisions {
+		if r.Name == name {
+			revision = r.Revision + 1
+		}
+	}
+
+	m.revisions = append(m.revisions, CannedResponseRevision{
+		Name:      name,
+		Revision:  revision,
+		Data:      data,
+		AuthorID:  authorID,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return revision, nil
+}
+
+func (m *MemoryStore) GetCannedResponses() ([]CannedResponseRevision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest := map[string]CannedResponseRevision{}
+	for _, r := range m.revisions {
+		latest[r.Name] = r
+	}
+
+	revisions := make([]CannedResponseRevision, 0, len(latest))
+	for _, r := range latest {
+		revisions = append(revisions, r)
+	}
+	slices.SortFunc(revisions, func(a, b CannedResponseRevision) int { return strings.Compare(a.Name, b.Name) })
+
+	return revisions, nil
+}
+
+func (m *MemoryStore) GetCannedResponseHistory(name string) ([]CannedResponseRevision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var revisions []CannedResponseRevision
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		if m.revisions[i].Name == name {
+			revisions = append(revisions, m.revisions[i])
+		}
+	}
+
+	return revisions, nil
+}
+
+func (m *MemoryStore) BanUser(ban TicketBan) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ban.BannedAt = time.Now().UTC()
+	m.bans[ban.UserID] = ban
+	return nil
+}
+
+func (m *MemoryStore) UnbanUser(userID tempest.Snowflake) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.bans[userID]
+	delete(m.bans, userID)
+	return ok, nil
+}
+
+func (m *MemoryStore) GetTicketBan(userID tempest.Snowflake) (TicketBan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ban, ok := m.bans[userID]
+	if !ok {
+		return TicketBan{}, sql.ErrNoRows
+	}
+
+	return ban, nil
+}
+
+func (m *MemoryStore) GetTicketBans() ([]TicketBan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bans := slices.Collect(maps.Values(m.bans))
+	slices.SortFunc(bans, func(a, b TicketBan) int { return b.BannedAt.Compare(a.BannedAt) })
+
+	return bans, nil
+}
+
+// Ping always succeeds, as the store is in memory
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// TryLockJob always grants the lock, as no other bot can use the store
+func (m *MemoryStore) TryLockJob(ctx context.Context, job string) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+// Close does nothing, as there is nothing to release
+func (m *MemoryStore) Close() error {
+	return nil
+}
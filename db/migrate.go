@@ -0,0 +1,146 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration files are named `NNNN_description.sql`, and are applied in order of their number.
+// Each database has its own directory of migrations, see dialect.migrations.
+// Never edit a migration that has been released; add a new one instead.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of ticketune supports")
+
+type migration struct {
+	version int    // The number prefix of the file name
+	name    string // The file name, for logging
+	sql     string // The statements to execute
+}
+
+// Read the embedded migration files in `dir`, sorted by version.
+// Errors if versions are not contiguous starting from 1.
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s is not named NNNN_description.sql", name)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s does not start with a number: %w", name, err)
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, sql: string(contents)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("expected migration %d, found %s", i+1, m.name)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Migrate applies every migration newer than the database's schema version, each in its own transaction.
+// Returns ErrSchemaTooNew if the database was migrated by a newer version of ticketune.
+func (d *DB) Migrate() error {
+	migrations, err := loadMigrations(d.dialect.migrations)
+	if err != nil {
+		return err
+	}
+
+	for {
+		done, err := d.applyNextMigration(migrations)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// Apply the migration following the database's schema version, if any, and bump the schema version, atomically.
+// The schema version is read in the same transaction, so that bots sharing a database never apply a migration twice.
+// Returns whether the schema was already up to date.
+func (d *DB) applyNextMigration(migrations []migration) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range []string{d.dialect.lockMigrations, d.dialect.createSchemaVersion} {
+		if statement == "" {
+			continue
+		}
+
+		_, err = tx.Exec(statement)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	var current int
+	err = tx.QueryRow(d.dialect.schemaVersion).Scan(&current)
+	if err != nil {
+		return false, err
+	}
+
+	latest := len(migrations)
+	if current > latest {
+		return false, fmt.Errorf("%w (database is at version %d, latest known is %d)", ErrSchemaTooNew, current, latest)
+	}
+	if current == latest {
+		return true, tx.Commit()
+	}
+
+	m := migrations[current]
+	slog.Info("applying database migration", "dialect", d.dialect.name, "migration", m.name)
+
+	_, err = tx.Exec(m.sql)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+	}
+
+	// The version is formatted into the statement, as SQLite's PRAGMA statements do not accept bound parameters
+	_, err = tx.Exec(fmt.Sprintf(d.dialect.setSchemaVersion, m.version))
+	if err != nil {
+		return false, fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+	}
+
+	return false, tx.Commit()
+}
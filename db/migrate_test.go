@@ -0,0 +1,243 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amatsagu/tempest"
+)
+
+// The schema created by versions of ticketune before schema migrations were introduced
+const baselineSchema = `CREATE TABLE IF NOT EXISTS support_tickets (
+	user_id TEXT PRIMARY KEY,
+	thread_id TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+)`
+
+// Create a SQLite database at `path` holding the baseline schema, with an open ticket for each user in `threads`
+func createBaselineDatabase(t *testing.T, path string, threads map[tempest.Snowflake]tempest.Snowflake) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(baselineSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for userID, threadID := range threads {
+		_, err = db.Exec(`INSERT INTO support_tickets (user_id, thread_id) VALUES (?, ?)`, userID, threadID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMigrateBaselineDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticketune-db.sqlite3")
+	threads := map[tempest.Snowflake]tempest.Snowflake{1001: 2001, 1002: 2002}
+	createBaselineDatabase(t, path, threads)
+
+	d, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("failed to migrate the baseline database: %v", err)
+	}
+	defer d.Close()
+
+	for userID, threadID := range threads {
+		tickets, err := d.GetUserTickets(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tickets) != 1 {
+			t.Fatalf("user %d has %d tickets after migrating, want 1", userID, len(tickets))
+		}
+
+		ticket := tickets[0]
+		if ticket.ThreadID != threadID || ticket.Status != TICKET_OPEN || ticket.Category != "password" || ticket.Locale != "en-US" {
+			t.Errorf("user %d has ticket %+v after migrating, want an open password ticket in thread %d", userID, ticket, threadID)
+		}
+	}
+
+	var tables int
+	err = d.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'support_tickets'`).Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Error("support_tickets still exists after migrating")
+	}
+}
+
+func TestMigrateSetsLatestVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticketune-db.sqlite3")
+	createBaselineDatabase(t, path, nil)
+
+	d, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	migrations, err := loadMigrations(sqliteDialect.migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var version int
+	err = d.db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("user_version is %d after migrating, want %d", version, len(migrations))
+	}
+
+	// Migrating again is a no-op
+	err = d.Migrate()
+	if err != nil {
+		t.Errorf("migrating an up to date database failed: %v", err)
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticketune-db.sqlite3")
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`PRAGMA user_version = 9999`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := OpenSQLite(path)
+	if err == nil {
+		d.Close()
+	}
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("opening a database with a newer schema returned %v, want ErrSchemaTooNew", err)
+	}
+}
+
+// The column of a table, as SQLite describes it. Types are left out, as they differ between the databases.
+type columnInfo struct {
+	Name       string
+	NotNull    bool
+	Default    sql.NullString
+	PrimaryKey int
+}
+
+// Describe the tables and indexes of a SQLite database, by name
+func describeSchema(t *testing.T, db *sql.DB) map[string][]columnInfo {
+	t.Helper()
+
+	rows, err := db.Query(`SELECT type, name, tbl_name FROM sqlite_master
+		WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type object struct{ kind, name, table string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		err = rows.Scan(&o.kind, &o.name, &o.table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+
+	schema := map[string][]columnInfo{}
+	for _, o := range objects {
+		if o.kind == "index" {
+			schema["index "+o.name+" on "+o.table] = nil
+			continue
+		}
+
+		columns, err := db.Query(fmt.Sprintf(`PRAGMA table_info(%q)`, o.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for columns.Next() {
+			var c columnInfo
+			var cid int
+			var columnType string
+			err = columns.Scan(&cid, &c.Name, &columnType, &c.NotNull, &c.Default, &c.PrimaryKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schema["table "+o.name] = append(schema["table "+o.name], c)
+		}
+		columns.Close()
+	}
+
+	return schema
+}
+
+// The PostgreSQL migration 0001 squashes the SQLite migrations 0001 to 0007, so both must create the same tables,
+// columns and indexes
+func TestPostgresSquashMatchesSQLite(t *testing.T) {
+	sqliteMigrations, err := loadMigrations(sqliteDialect.migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgresMigrations, err := loadMigrations(postgresDialect.migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrated.sqlite3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrated.Close()
+	for _, m := range sqliteMigrations[:7] {
+		_, err = migrated.Exec(m.sql)
+		if err != nil {
+			t.Fatalf("failed to apply %s: %v", m.name, err)
+		}
+	}
+
+	// SQLite has no identity columns, but INTEGER PRIMARY KEY columns are generated all the same
+	squash := strings.ReplaceAll(postgresMigrations[0].sql, "BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY", "INTEGER PRIMARY KEY")
+	squashed, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "squashed.sqlite3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer squashed.Close()
+	_, err = squashed.Exec(squash)
+	if err != nil {
+		t.Fatalf("failed to apply %s: %v", postgresMigrations[0].name, err)
+	}
+
+	want, got := describeSchema(t, migrated), describeSchema(t, squashed)
+	for name, columns := range want {
+		if !reflect.DeepEqual(got[name], columns) {
+			t.Errorf("%s is\n%+v\nin the PostgreSQL squash, want\n%+v", name, got[name], columns)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s is only in the PostgreSQL squash", name)
+		}
+	}
+}
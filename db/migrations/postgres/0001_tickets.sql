@@ -0,0 +1,53 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- The schema the SQLite database has after its migration 0007, for deployments sharing a PostgreSQL database.
+-- Discord IDs are stored as BIGINT, which holds every snowflake Discord will issue before 2084.
+CREATE TABLE tickets (
+	ticket_number BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	thread_id BIGINT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+	opened_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	closed_at TIMESTAMPTZ,
+	closed_by BIGINT,
+	assigned_to BIGINT,
+	assigned_at TIMESTAMPTZ,
+	locale TEXT NOT NULL DEFAULT 'en-US'
+);
+
+-- Users may only have one open ticket at a time
+CREATE UNIQUE INDEX tickets_one_open_per_user ON tickets (user_id) WHERE status = 'open';
+CREATE INDEX tickets_thread_id ON tickets (thread_id);
+
+-- Where the transcript of a closed ticket was posted. Attachment URLs expire, so the message is fetched again when needed.
+CREATE TABLE transcripts (
+	ticket_number BIGINT PRIMARY KEY REFERENCES tickets (ticket_number),
+	channel_id BIGINT NOT NULL,
+	message_id BIGINT NOT NULL,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Actions the bot took on a ticket by itself, such as inactivity reminders and automatic closing
+CREATE TABLE ticket_events (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	ticket_number BIGINT NOT NULL REFERENCES tickets (ticket_number),
+	kind TEXT NOT NULL,
+	message_id BIGINT,
+	details TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX ticket_events_ticket_kind ON ticket_events (ticket_number, kind);
+
+-- Every version of the canned responses helpers saved with /response.
+-- The latest revision of a name is the current one; a revision without data deleted the response.
+CREATE TABLE canned_response_revisions (
+	name TEXT NOT NULL,
+	revision BIGINT NOT NULL,
+	data TEXT,
+	author_id BIGINT NOT NULL,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	PRIMARY KEY (name, revision)
+);
@@ -0,0 +1,13 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- The answers a user gave in the intake form when opening a ticket, in the order the questions were asked.
+-- The question is stored as it was asked, so that the answers still make sense if the form changes.
+CREATE TABLE ticket_intake_answers (
+	ticket_number BIGINT NOT NULL REFERENCES tickets (ticket_number),
+	position INTEGER NOT NULL,
+	question TEXT NOT NULL,
+	answer TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (ticket_number, position)
+);
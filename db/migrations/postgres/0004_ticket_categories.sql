@@ -0,0 +1,11 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Tickets belong to one of the categories configured in [[categories]]. The tickets opened before categories existed
+-- were all password tickets.
+ALTER TABLE tickets ADD COLUMN category TEXT NOT NULL DEFAULT 'password';
+
+-- Users may only have one open ticket at a time in each category
+DROP INDEX tickets_one_open_per_user;
+CREATE UNIQUE INDEX tickets_one_open_per_user_category ON tickets (user_id, category) WHERE status = 'open';
@@ -0,0 +1,10 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Baseline schema, as created by versions of ticketune before schema migrations were introduced
+CREATE TABLE IF NOT EXISTS support_tickets (
+	user_id TEXT PRIMARY KEY,
+	thread_id TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
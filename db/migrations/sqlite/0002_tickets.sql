@@ -0,0 +1,23 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Keep a row for every ticket ever opened instead of one row per open ticket
+CREATE TABLE IF NOT EXISTS tickets (
+	ticket_number INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id TEXT NOT NULL,
+	thread_id TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+	opened_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	closed_at DATETIME,
+	closed_by TEXT
+);
+
+-- Users may only have one open ticket at a time
+CREATE UNIQUE INDEX IF NOT EXISTS tickets_one_open_per_user ON tickets (user_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS tickets_thread_id ON tickets (thread_id);
+
+INSERT OR IGNORE INTO tickets (user_id, thread_id, opened_at)
+	SELECT user_id, thread_id, created_at FROM support_tickets ORDER BY created_at;
+
+DROP TABLE support_tickets;
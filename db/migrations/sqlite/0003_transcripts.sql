@@ -0,0 +1,11 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Where the transcript of a closed ticket was posted. Attachment URLs expire, so the message is fetched again when needed.
+CREATE TABLE IF NOT EXISTS transcripts (
+	ticket_number INTEGER PRIMARY KEY REFERENCES tickets (ticket_number),
+	channel_id TEXT NOT NULL,
+	message_id TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,7 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- The helper who claimed or was assigned the ticket
+ALTER TABLE tickets ADD COLUMN assigned_to TEXT;
+ALTER TABLE tickets ADD COLUMN assigned_at DATETIME;
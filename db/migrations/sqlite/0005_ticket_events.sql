@@ -0,0 +1,15 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Actions the bot took on a ticket by itself, such as inactivity reminders and automatic closing
+CREATE TABLE IF NOT EXISTS ticket_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	ticket_number INTEGER NOT NULL REFERENCES tickets (ticket_number),
+	kind TEXT NOT NULL,
+	message_id TEXT,
+	details TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ticket_events_ticket_kind ON ticket_events (ticket_number, kind);
@@ -0,0 +1,6 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- The Discord locale of the user when they opened the ticket, used to pick which translation to send them
+ALTER TABLE tickets ADD COLUMN locale TEXT NOT NULL DEFAULT 'en-US';
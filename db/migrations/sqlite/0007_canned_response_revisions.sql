@@ -0,0 +1,14 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Every version of the canned responses helpers saved with /response.
+-- The latest revision of a name is the current one; a revision without data deleted the response.
+CREATE TABLE canned_response_revisions (
+	name TEXT NOT NULL,
+	revision INTEGER NOT NULL,
+	data TEXT,
+	author_id TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	PRIMARY KEY (name, revision)
+);
@@ -0,0 +1,12 @@
+-- SPDX-FileCopyrightText: 2025 Pagefault Games
+--
+-- SPDX-License-Identifier: AGPL-3.0-or-later
+
+-- Users banned from opening tickets with /ticket-ban. A ban without an expiry is permanent.
+CREATE TABLE ticket_bans (
+	user_id TEXT PRIMARY KEY,
+	reason TEXT NOT NULL DEFAULT '',
+	banned_by TEXT NOT NULL,
+	banned_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	expires_at DATETIME
+);
@@ -0,0 +1,125 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pagefaultgames/ticketune/config"
+
+	"github.com/amatsagu/tempest"
+)
+
+// TicketStore holds tickets, where their transcripts were posted, and the actions the bot took on them.
+// Methods looking up a single ticket, transcript or event return sql.ErrNoRows when there is none, as do the ones
+// of the other stores below.
+type TicketStore interface {
+	// OpenTicket records a new open ticket for a user in a category, in the user's locale, and returns its ticket number.
+	// Any ticket still marked open for the user in the category is closed first.
+	OpenTicket(userID tempest.Snowflake, category string, threadID tempest.Snowflake, locale tempest.Language) (int64, error)
+	// GetUserThread returns the thread ID of the user's open ticket in a category.
+	GetUserThread(userID tempest.Snowflake, category string) (tempest.Snowflake, error)
+	// GetThreadUser returns the user ID associated with the open ticket in a thread.
+	GetThreadUser(threadID tempest.Snowflake) (tempest.Snowflake, error)
+	// GetUserTickets returns every ticket (open or closed) opened by a user, newest first.
+	GetUserTickets(userID tempest.Snowflake) ([]Ticket, error)
+	// GetThreadTicket returns the open ticket associated with a thread ID.
+	GetThreadTicket(threadID tempest.Snowflake) (Ticket, error)
+	// GetOpenTickets returns every open ticket, oldest first.
+	GetOpenTickets() ([]Ticket, error)
+	// CloseUserTicket marks a user's open tickets as closed, in every category, without recording a closing helper.
+	CloseUserTicket(userID tempest.Snowflake) error
+	// CloseThread marks the open ticket in a thread as closed by `closedBy` (0 for the bot), and returns its user ID.
+	CloseThread(threadID tempest.Snowflake, closedBy tempest.Snowflake) (tempest.Snowflake, error)
+	// ClaimTicket records `helperID` as the helper working on the open ticket in a thread, unless the ticket is assigned
+	// already, in which case it returns the ticket unchanged with ErrTicketClaimed.
+	ClaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error)
+	// UnclaimTicket unassigns the open ticket in a thread, unless its assignee is no longer `helperID`, in which case it
+	// returns the ticket unchanged with ErrTicketClaimed.
+	UnclaimTicket(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error)
+	// SetTicketAssignee records `helperID` (0 to unassign) as the helper working on the open ticket in a thread,
+	// whoever was assigned before. Only for /assign: claiming and unclaiming go through the methods above.
+	SetTicketAssignee(threadID tempest.Snowflake, helperID tempest.Snowflake) (Ticket, error)
+
+	// SaveTranscript records the message a ticket's transcript was posted in.
+	SaveTranscript(ticketNumber int64, channelID tempest.Snowflake, messageID tempest.Snowflake) error
+	// GetTranscript returns the channel and message ID a ticket's transcript was posted in.
+	GetTranscript(ticketNumber int64) (tempest.Snowflake, tempest.Snowflake, error)
+
+	// SaveTicketIntake records the answers a user gave in the intake form when opening a ticket, replacing any saved before.
+	SaveTicketIntake(ticketNumber int64, answers []IntakeAnswer) error
+	// GetTicketIntake returns the answers a user gave in the intake form when opening a ticket, in the order they were asked.
+	GetTicketIntake(ticketNumber int64) ([]IntakeAnswer, error)
+
+	// LogTicketEvent records an action the bot took on a ticket. `messageID` may be 0.
+	LogTicketEvent(ticketNumber int64, kind TicketEventKind, messageID tempest.Snowflake, details string) error
+	// GetLastTicketEvent returns the most recent event of the given kind for a ticket.
+	GetLastTicketEvent(ticketNumber int64, kind TicketEventKind) (TicketEvent, error)
+}
+
+// CannedResponseStore holds every revision of the canned responses edited with /response
+type CannedResponseStore interface {
+	// SaveCannedResponseRevision records a new version of a canned response and returns its revision number.
+	// An empty `data` records that the response was deleted.
+	SaveCannedResponseRevision(name string, data string, authorID tempest.Snowflake) (int64, error)
+	// GetCannedResponses returns the latest revision of every canned response, including deletions, by name.
+	GetCannedResponses() ([]CannedResponseRevision, error)
+	// GetCannedResponseHistory returns every revision of a canned response, newest first.
+	GetCannedResponseHistory(name string) ([]CannedResponseRevision, error)
+}
+
+// TicketBanStore holds the users banned from opening tickets.
+// Bans are kept after they expire, until they are replaced or lifted.
+type TicketBanStore interface {
+	// BanUser bans a user from opening tickets, replacing the ban they already have, if any.
+	BanUser(ban TicketBan) error
+	// UnbanUser lifts a user's ban, and returns whether they had one (even an expired one).
+	UnbanUser(userID tempest.Snowflake) (bool, error)
+	// GetTicketBan returns a user's ban, which may have expired.
+	GetTicketBan(userID tempest.Snowflake) (TicketBan, error)
+	// GetTicketBans returns every ban, including expired ones, newest first.
+	GetTicketBans() ([]TicketBan, error)
+}
+
+// Store is everything the bot keeps between restarts
+type Store interface {
+	TicketStore
+	CannedResponseStore
+	TicketBanStore
+	// TryLockJob takes the lock of a background job, so that bots sharing the store don't run it at the same time, and
+	// returns false if another bot holds it. Call unlock once the job is done.
+	// Stores a single bot can use, like SQLite's, always grant the lock.
+	TryLockJob(ctx context.Context, job string) (unlock func(), ok bool, err error)
+	// Ping returns an error if the store cannot be reached
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// ErrTicketClaimed is returned when claiming or unclaiming a ticket whose assignee changed since it was looked up
+var ErrTicketClaimed = errors.New("ticket was claimed by another helper")
+
+var (
+	_ Store = (*DB)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+// Open the store selected by the configuration, bringing its schema up to date
+func Open(cfg config.Database) (Store, error) {
+	switch cfg.Driver {
+	case config.DRIVER_SQLITE:
+		return OpenSQLite(cfg.Path)
+	case config.DRIVER_POSTGRES:
+		return OpenPostgres(cfg.URL)
+	case config.DRIVER_MEMORY:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// Run a test against a fresh SQLite database and a MemoryStore, which must behave the same
+func testStores(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("sqlite", func(t *testing.T) {
+		d, err := OpenSQLite(filepath.Join(t.TempDir(), "ticketune-db.sqlite3"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+
+		test(t, d)
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemoryStore())
+	})
+}
+
+func TestClaimTicket(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		_, err := store.OpenTicket(1001, "password", 2001, "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ticket, err := store.ClaimTicket(2001, 3001)
+		if err != nil || ticket.Assignee != 3001 {
+			t.Fatalf("claiming an unclaimed ticket returned %+v, %v", ticket, err)
+		}
+
+		ticket, err = store.ClaimTicket(2001, 3002)
+		if err != ErrTicketClaimed || ticket.Assignee != 3001 {
+			t.Errorf("claiming a claimed ticket returned %+v, %v, want the first claim and ErrTicketClaimed", ticket, err)
+		}
+
+		_, err = store.ClaimTicket(2002, 3001)
+		if err != sql.ErrNoRows {
+			t.Errorf("claiming a missing ticket returned %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+func TestUnclaimTicket(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		_, err := store.OpenTicket(1001, "password", 2001, "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = store.ClaimTicket(2001, 3001)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The ticket was reassigned since the unclaiming helper looked it up
+		_, err = store.SetTicketAssignee(2001, 3002)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ticket, err := store.UnclaimTicket(2001, 3001)
+		if err != ErrTicketClaimed || ticket.Assignee != 3002 {
+			t.Errorf("unclaiming a reassigned ticket returned %+v, %v, want the new assignee and ErrTicketClaimed", ticket, err)
+		}
+
+		ticket, err = store.UnclaimTicket(2001, 3002)
+		if err != nil || ticket.Assignee != 0 {
+			t.Errorf("unclaiming a ticket returned %+v, %v", ticket, err)
+		}
+	})
+}
+
+// Only one bot uses SQLite databases and memory stores, so background jobs never wait on each other
+func TestTryLockJob(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		for range 2 {
+			unlock, ok, err := store.TryLockJob(t.Context(), "inactivity")
+			if err != nil || !ok {
+				t.Fatalf("locking a job returned %v, %v, want the lock", ok, err)
+			}
+			defer unlock()
+		}
+	})
+}
+
+func TestSaveCannedResponseRevision(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		for want := int64(1); want <= 2; want++ {
+			revision, err := store.SaveCannedResponseRevision("greet", `{"name": "greet"}`, 3001)
+			if err != nil || revision != want {
+				t.Errorf("saving a revision returned %d, %v, want revision %d", revision, err, want)
+			}
+		}
+	})
+}
+
+// Saving a canned response retries when another bot saved the same revision number first, which it detects
+// from the database's error
+func TestIsUniqueViolation(t *testing.T) {
+	d, err := OpenSQLite(filepath.Join(t.TempDir(), "ticketune-db.sqlite3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	insert := `INSERT INTO canned_response_revisions (name, revision, data, author_id) VALUES ('greet', 1, '{}', 3001)`
+	_, err = d.db.Exec(insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.db.Exec(insert)
+	if err == nil || !d.dialect.isUniqueViolation(err) {
+		t.Errorf("saving the same revision twice returned %v, want a unique violation", err)
+	}
+	if d.dialect.isUniqueViolation(sql.ErrNoRows) {
+		t.Error("sql.ErrNoRows was taken for a unique violation")
+	}
+}
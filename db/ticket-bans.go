@@ -0,0 +1,95 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+// TicketBan is a user banned from opening tickets with /ticket-ban
+type TicketBan struct {
+	UserID    tempest.Snowflake // The banned user
+	Reason    string            // Why the user was banned, shown to helpers only
+	BannedBy  tempest.Snowflake // The helper who banned the user
+	BannedAt  time.Time         // When the user was banned
+	ExpiresAt sql.NullTime      // When the ban ends, or null if it is permanent
+}
+
+// Active returns whether the ban is still in effect at `now`
+func (b TicketBan) Active(now time.Time) bool {
+	return !b.ExpiresAt.Valid || now.Before(b.ExpiresAt.Time)
+}
+
+const ticketBanColumns = `user_id, reason, banned_by, banned_at, expires_at`
+
+// Scan a row selected with ticketBanColumns into a TicketBan
+func scanTicketBan(row interface{ Scan(...any) error }) (TicketBan, error) {
+	var b TicketBan
+	err := row.Scan(&b.UserID, &b.Reason, &b.BannedBy, &b.BannedAt, &b.ExpiresAt)
+	return b, err
+}
+
+// BanUser bans a user from opening tickets, replacing the ban they already have, if any.
+// The BannedAt of `ban` is ignored, and set to the current time.
+func (d *DB) BanUser(ban TicketBan) error {
+	_, err := d.db.Exec(
+		d.bind(`INSERT INTO ticket_bans (user_id, reason, banned_by, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE
+		SET reason = excluded.reason, banned_by = excluded.banned_by, banned_at = CURRENT_TIMESTAMP, expires_at = excluded.expires_at`),
+		ban.UserID,
+		ban.Reason,
+		ban.BannedBy,
+		ban.ExpiresAt,
+	)
+
+	return err
+}
+
+// UnbanUser lifts a user's ban, and returns whether they had one (even an expired one).
+func (d *DB) UnbanUser(userID tempest.Snowflake) (bool, error) {
+	result, err := d.db.Exec(d.bind(`DELETE FROM ticket_bans WHERE user_id = ?`), userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// GetTicketBan returns a user's ban, which may have expired, or sql.ErrNoRows if they were never banned.
+func (d *DB) GetTicketBan(userID tempest.Snowflake) (TicketBan, error) {
+	row := d.db.QueryRow(
+		d.bind(`SELECT `+ticketBanColumns+` FROM ticket_bans WHERE user_id = ?`),
+		userID,
+	)
+
+	return scanTicketBan(row)
+}
+
+// GetTicketBans returns every ban, including expired ones, newest first.
+func (d *DB) GetTicketBans() ([]TicketBan, error) {
+	rows, err := d.db.Query(`SELECT ` + ticketBanColumns + ` FROM ticket_bans ORDER BY banned_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bans []TicketBan
+	for rows.Next() {
+		b, err := scanTicketBan(rows)
+		if err != nil {
+			return nil, err
+		}
+		bans = append(bans, b)
+	}
+
+	return bans, rows.Err()
+}
@@ -0,0 +1,68 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package db
+
+// IntakeAnswer is a user's answer to a question of the intake form shown when opening a ticket
+type IntakeAnswer struct {
+	Question string // The question, as it was asked
+	Answer   string // The user's answer, empty if they skipped the question
+}
+
+// SaveTicketIntake records the answers a user gave in the intake form when opening a ticket, replacing any saved before.
+func (d *DB) SaveTicketIntake(ticketNumber int64, answers []IntakeAnswer) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(d.bind(`DELETE FROM ticket_intake_answers WHERE ticket_number = ?`), ticketNumber)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range answers {
+		_, err = tx.Exec(
+			d.bind(`INSERT INTO ticket_intake_answers (ticket_number, position, question, answer) VALUES (?, ?, ?, ?)`),
+			ticketNumber,
+			i,
+			a.Question,
+			a.Answer,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetTicketIntake returns the answers a user gave in the intake form when opening a ticket, in the order they were asked.
+// Tickets opened before the intake form existed have none.
+func (d *DB) GetTicketIntake(ticketNumber int64) ([]IntakeAnswer, error) {
+	rows, err := d.db.Query(
+		d.bind(`SELECT question, answer FROM ticket_intake_answers WHERE ticket_number = ? ORDER BY position`),
+		ticketNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []IntakeAnswer
+	for rows.Next() {
+		var a IntakeAnswer
+		err := rows.Scan(&a.Question, &a.Answer)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
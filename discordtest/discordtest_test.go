@@ -0,0 +1,200 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package discordtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/commands"
+	"github.com/pagefaultgames/ticketune/config"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/discordtest"
+	"github.com/pagefaultgames/ticketune/types"
+
+	"github.com/amatsagu/tempest"
+)
+
+const (
+	applicationID       tempest.Snowflake = 100
+	guildID             tempest.Snowflake = 101
+	helperRoleID        tempest.Snowflake = 102
+	ticketChannelID     tempest.Snowflake = 103
+	troubleshootingID   tempest.Snowflake = 104
+	transcriptChannelID tempest.Snowflake = 105
+)
+
+// How long to wait for the requests handlers make after answering the interaction
+const waitTimeout = 5 * time.Second
+
+// Open a password ticket with the Open Ticket button and its intake form, then close it with /close, through the
+// tempest HTTP client like Discord would
+func TestOpenAndCloseTicket(t *testing.T) {
+	srv := discordtest.NewServer()
+	defer srv.Close()
+
+	signer, err := discordtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Discord.Token = "MTAw.test.token"
+	cfg.Discord.PublicKey = signer.PublicKey()
+	cfg.Discord.GuildID = guildID
+	cfg.Discord.HelperRoleID = helperRoleID
+	cfg.Discord.TroubleshootingChannelID = troubleshootingID
+	cfg.Tickets.ChannelID = ticketChannelID
+	cfg.Tickets.TranscriptChannelID = transcriptChannelID
+
+	bot := commands.New(&cfg, db.NewMemoryStore(), nil)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+		defer cancel()
+		bot.Shutdown(ctx)
+	}()
+
+	client := tempest.NewHTTPClient(tempest.HTTPClientOptions{
+		BaseClientOptions: tempest.BaseClientOptions{
+			Token:            cfg.Discord.Token,
+			ComponentHandler: bot.HandleDynamicComponent,
+		},
+		PublicKey: cfg.Discord.PublicKey,
+	})
+	srv.Attach(&client.BaseClient)
+
+	client.RegisterComponent([]string{commands.OpenTicketButtonID(config.PASSWORD_CATEGORY)}, bot.Component(bot.OpenTicketButtonCallback))
+	err = client.RegisterModal(commands.OpenTicketModalID(config.PASSWORD_CATEGORY), bot.Modal(bot.HandleOpenTicketModal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.RegisterCommand(bot.Command(bot.CloseCommand()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []tempest.Snowflake{ticketChannelID, troubleshootingID, transcriptChannelID} {
+		srv.AddChannel(types.Channel{ID: id, Type: tempest.GUILD_TEXT_CHANNEL_TYPE, GuildID: guildID})
+	}
+	user := tempest.User{ID: 200, Username: "player"}
+	helper := tempest.User{ID: 201, Username: "helper"}
+	srv.AddUser(user)
+	srv.AddUser(helper)
+
+	userOrigin := discordtest.Origin{
+		ApplicationID: applicationID,
+		GuildID:       guildID,
+		ChannelID:     ticketChannelID,
+		Member:        &tempest.Member{User: &user},
+	}
+
+	// The button answers with the intake form
+	w, err := signer.Send(client.DiscordRequestHandler, discordtest.ComponentInteraction(userOrigin, commands.OpenTicketButtonID(config.PASSWORD_CATEGORY)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response struct {
+		Type tempest.ResponseType `json:"type"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil || response.Type != tempest.MODAL_RESPONSE_TYPE {
+		t.Fatalf("the Open Ticket button answered %d %s, want the intake form", w.Code, w.Body.String())
+	}
+
+	// Submitting the form opens the ticket
+	w, err = signer.Send(client.DiscordRequestHandler, discordtest.ModalInteraction(userOrigin, commands.OpenTicketModalID(config.PASSWORD_CATEGORY),
+		textInput("intake-username", "player123"),
+		textInput("intake-platform", "Android, Chrome"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "A new ticket has been created") {
+		t.Fatalf("submitting the intake form answered %d %s, want the new ticket", w.Code, w.Body.String())
+	}
+
+	var thread types.Channel
+	opened := srv.Wait(waitTimeout, func() bool {
+		threads := srv.Threads(ticketChannelID)
+		if len(threads) != 1 {
+			return false
+		}
+		thread = threads[0]
+		return len(srv.Messages(thread.ID)) > 0 && slices.Contains(srv.ThreadMembers(thread.ID), user.ID)
+	})
+	if !opened {
+		t.Fatalf("no ticket thread with the instructions and the user was created, threads: %+v", srv.Threads(ticketChannelID))
+	}
+	if thread.Name != "Password Help - player" {
+		t.Errorf("the ticket thread is named %q, want %q", thread.Name, "Password Help - player")
+	}
+	if _, ok := srv.Permissions(ticketChannelID, user.ID); !ok {
+		t.Error("the user was not given access to the ticket channel")
+	}
+
+	raw, _ := json.Marshal(srv.Messages(thread.ID)[0])
+	if !strings.Contains(string(raw), "player123") {
+		t.Errorf("the instructions message does not include the intake form answers: %s", raw)
+	}
+
+	// The user replies, then a helper closes the ticket
+	srv.SendMessage(thread.ID, user, "here is my screenshot")
+
+	helperOrigin := discordtest.Origin{
+		ApplicationID: applicationID,
+		GuildID:       guildID,
+		ChannelID:     thread.ID,
+		Member:        &tempest.Member{User: &helper, RoleIDs: []tempest.Snowflake{helperRoleID}},
+	}
+	_, err = signer.Send(client.DiscordRequestHandler, discordtest.CommandInteraction(helperOrigin, "close"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := srv.Wait(waitTimeout, func() bool {
+		_, exists := srv.Channel(thread.ID)
+		return !exists
+	})
+	if !closed {
+		t.Fatal("the ticket thread was not deleted")
+	}
+
+	transcripts := srv.Messages(transcriptChannelID)
+	if len(transcripts) != 1 || len(transcripts[0].Attachments) == 0 {
+		t.Fatalf("want one transcript message with attachments, got %+v", transcripts)
+	}
+	for _, attachment := range transcripts[0].Attachments {
+		transcript, ok := srv.File(attachment.URL)
+		if !ok || !strings.Contains(string(transcript), "here is my screenshot") {
+			t.Errorf("the transcript %s does not include the user's reply: %s", attachment.FileName, transcript)
+		}
+	}
+	if _, ok := srv.Permissions(ticketChannelID, user.ID); ok {
+		t.Error("the user's access to the ticket channel was not removed")
+	}
+	if alerts := srv.Messages(troubleshootingID); len(alerts) != 0 {
+		t.Errorf("want no alerts, got %+v", alerts)
+	}
+}
+
+// A text input of a submitted modal, as Discord sends it
+func textInput(customID, value string) tempest.LayoutComponent {
+	return tempest.LabelComponent{
+		Type: tempest.LABEL_COMPONENT_TYPE,
+		Component: tempest.TextInputComponent{
+			Type:     tempest.TEXT_INPUT_COMPONENT_TYPE,
+			CustomID: customID,
+			Value:    value,
+		},
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package discordtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Signer signs interaction payloads the way Discord does, with a key generated for the test.
+// Give PublicKey() to the tempest HTTP client so it accepts the signed interactions.
+type Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewSigner generates a new signing key
+func NewSigner() (*Signer, error) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{privateKey: privateKey}, nil
+}
+
+// PublicKey returns the hex encoded public key, as shown in the Discord developer portal
+func (s *Signer) PublicKey() string {
+	return hex.EncodeToString(s.privateKey.Public().(ed25519.PublicKey))
+}
+
+// Sign sets the signature headers Discord sends with every interaction on a request with the given body
+// https://discord.com/developers/docs/interactions/overview#setting-up-an-endpoint-validating-security-request-headers
+func (s *Signer) Sign(r *http.Request, body []byte) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ed25519.Sign(s.privateKey, append([]byte(timestamp), body...))
+
+	r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	r.Header.Set("X-Signature-Timestamp", timestamp)
+}
+
+// NewRequest returns a signed request delivering the interaction to the interactions endpoint
+func (s *Signer) NewRequest(interaction tempest.Interaction) (*http.Request, error) {
+	body, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/discord/callback", bytes.NewReader(body))
+	r.Header.Set("Content-Type", tempest.CONTENT_TYPE_JSON)
+	s.Sign(r, body)
+
+	return r, nil
+}
+
+// Send delivers the interaction to `handler` (usually the tempest client's DiscordRequestHandler) and returns the
+// recorded HTTP response, which holds the initial interaction response.
+func (s *Signer) Send(handler http.HandlerFunc, interaction tempest.Interaction) (*httptest.ResponseRecorder, error) {
+	r, err := s.NewRequest(interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	return w, nil
+}
+
+// Origin describes where an interaction comes from: who triggered it, and in which channel
+type Origin struct {
+	ApplicationID tempest.Snowflake
+	GuildID       tempest.Snowflake
+	ChannelID     tempest.Snowflake
+	Member        *tempest.Member
+	Locale        tempest.Language
+}
+
+var lastInteractionID atomic.Uint64
+
+// Build an interaction of the given type and data, with a unique ID and token
+func newInteraction(origin Origin, interactionType tempest.InteractionType, data any) tempest.Interaction {
+	raw, _ := json.Marshal(data)
+	id := tempest.Snowflake(lastInteractionID.Add(1))
+
+	var user *tempest.User
+	if origin.Member != nil {
+		user = origin.Member.User
+	}
+
+	return tempest.Interaction{
+		ID:            id,
+		ApplicationID: origin.ApplicationID,
+		Type:          interactionType,
+		Data:          raw,
+		GuildID:       origin.GuildID,
+		ChannelID:     origin.ChannelID,
+		Member:        origin.Member,
+		User:          user,
+		Token:         "interaction-token-" + id.String(),
+		Locale:        origin.Locale,
+	}
+}
+
+// CommandInteraction builds the interaction Discord sends when a slash command is used
+func CommandInteraction(origin Origin, name string, options ...tempest.CommandInteractionOption) tempest.Interaction {
+	return newInteraction(origin, tempest.APPLICATION_COMMAND_INTERACTION_TYPE, tempest.CommandInteractionData{
+		Name:    name,
+		Type:    tempest.CHAT_INPUT_COMMAND_TYPE,
+		Options: options,
+		GuildID: origin.GuildID,
+	})
+}
+
+// AutoCompleteInteraction builds the interaction Discord sends while a member types the option `focused`
+// of a slash command, which is marked as focused in `options`
+func AutoCompleteInteraction(origin Origin, name string, focused string, options ...tempest.CommandInteractionOption) tempest.Interaction {
+	for i := range options {
+		options[i].Focused = options[i].Name == focused
+	}
+
+	return newInteraction(origin, tempest.APPLICATION_COMMAND_AUTO_COMPLETE_INTERACTION_TYPE, tempest.CommandInteractionData{
+		Name:    name,
+		Type:    tempest.CHAT_INPUT_COMMAND_TYPE,
+		Options: options,
+		GuildID: origin.GuildID,
+	})
+}
+
+// Option builds a slash command option with a string, number or boolean value
+func Option(name string, value any) tempest.CommandInteractionOption {
+	option := tempest.CommandInteractionOption{Name: name, Value: value}
+	switch value.(type) {
+	case string:
+		option.Type = tempest.STRING_OPTION_TYPE
+	case int:
+		option.Type = tempest.INTEGER_OPTION_TYPE
+	case float64:
+		option.Type = tempest.NUMBER_OPTION_TYPE
+	case bool:
+		option.Type = tempest.BOOLEAN_OPTION_TYPE
+	}
+
+	return option
+}
+
+// ComponentInteraction builds the interaction Discord sends when a button is pressed
+func ComponentInteraction(origin Origin, customID string) tempest.Interaction {
+	return newInteraction(origin, tempest.MESSAGE_COMPONENT_INTERACTION_TYPE, tempest.ComponentInteractionData{
+		CustomID: customID,
+		Type:     tempest.BUTTON_COMPONENT_TYPE,
+	})
+}
+
+// ModalInteraction builds the interaction Discord sends when a modal is submitted
+func ModalInteraction(origin Origin, customID string, components ...tempest.LayoutComponent) tempest.Interaction {
+	return newInteraction(origin, tempest.MODAL_SUBMIT_INTERACTION_TYPE, tempest.ModalInteractionData{
+		CustomID:   customID,
+		Components: components,
+	})
+}
@@ -0,0 +1,811 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// An in-process fake of the Discord REST API endpoints ticketune uses, and helpers to send signed interactions
+// to a tempest HTTP client, so whole flows can be exercised without Discord
+
+package discordtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pagefaultgames/ticketune/types"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Request is a request the fake server received, as sent by tempest
+type Request struct {
+	Method string
+	Path   string // Relative to the API's base URL, e.g. "/channels/123/messages"
+	Query  url.Values
+	Body   []byte // The JSON payload, or the payload_json part of multipart requests
+}
+
+// The body of a request: its JSON payload, and the files attached to multipart requests
+type payload struct {
+	json  []byte
+	files []file
+}
+
+type file struct {
+	name    string
+	content []byte
+}
+
+// Server is a fake of the Discord REST API, keeping channels, messages and thread members in memory.
+// Point a tempest client at it with Attach.
+type Server struct {
+	*httptest.Server
+
+	BotUser tempest.User // The author of the messages sent by the bot
+
+	mu            sync.Mutex
+	changed       *sync.Cond // Broadcast after every request
+	version       int        // Incremented after every request
+	lastID        tempest.Snowflake
+	channels      map[tempest.Snowflake]types.Channel
+	messages      map[tempest.Snowflake][]tempest.Message // By channel, oldest first
+	threadMembers map[tempest.Snowflake][]tempest.Snowflake
+	permissions   map[tempest.Snowflake]map[tempest.Snowflake]json.RawMessage // Permission overwrites, by channel then user
+	users         map[tempest.Snowflake]tempest.User
+	responses     map[string][]json.RawMessage           // Interaction responses sent through the REST API, by interaction token
+	commands      map[tempest.Snowflake][]map[string]any // Application commands, by guild (0 for global commands)
+	files         map[string][]byte                      // Attachment contents, by URL path
+	failures      map[string]int                         // Statuses answered instead of handling requests, by "METHOD path"
+	requests      []Request
+}
+
+// Prefix of the paths of the fake API, so that routes match DISCORD_API_URL
+const apiPrefix = "/api/v10"
+
+// NewServer starts a fake Discord API. The caller must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		BotUser:       tempest.User{ID: 1, Username: "ticketune", Bot: true},
+		channels:      map[tempest.Snowflake]types.Channel{},
+		messages:      map[tempest.Snowflake][]tempest.Message{},
+		threadMembers: map[tempest.Snowflake][]tempest.Snowflake{},
+		permissions:   map[tempest.Snowflake]map[tempest.Snowflake]json.RawMessage{},
+		users:         map[tempest.Snowflake]tempest.User{},
+		responses:     map[string][]json.RawMessage{},
+		commands:      map[tempest.Snowflake][]map[string]any{},
+		files:         map[string][]byte{},
+		failures:      map[string]int{},
+	}
+	s.changed = sync.NewCond(&s.mu)
+
+	mux := http.NewServeMux()
+	route := func(pattern string, handler func(r *http.Request, body payload) (int, any)) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" "+apiPrefix+path, s.handle(handler))
+	}
+
+	route("GET /channels/{channel}", s.getChannel)
+	route("PATCH /channels/{channel}", s.modifyChannel)
+	route("DELETE /channels/{channel}", s.deleteChannel)
+	route("GET /channels/{channel}/messages", s.getMessages)
+	route("POST /channels/{channel}/messages", s.createMessage)
+	route("GET /channels/{channel}/messages/{message}", s.getMessage)
+	route("PATCH /channels/{channel}/messages/{message}", s.editMessage)
+	route("DELETE /channels/{channel}/messages/{message}", s.deleteMessage)
+	route("POST /channels/{channel}/threads", s.createThread)
+	route("GET /channels/{channel}/thread-members/{user}", s.getThreadMember)
+	route("PUT /channels/{channel}/thread-members/{user}", s.addThreadMember)
+	route("DELETE /channels/{channel}/thread-members/{user}", s.removeThreadMember)
+	route("PUT /channels/{channel}/permissions/{user}", s.setPermissions)
+	route("DELETE /channels/{channel}/permissions/{user}", s.deletePermissions)
+	route("GET /users/{user}", s.getUser)
+	route("POST /interactions/{interaction}/{token}/callback", s.interactionResponse)
+	route("POST /webhooks/{application}/{token}", s.interactionResponse)
+	route("PATCH /webhooks/{application}/{token}/messages/{message}", s.interactionResponse)
+	route("DELETE /webhooks/{application}/{token}/messages/{message}", s.interactionResponse)
+	route("PUT /applications/{application}/commands", s.overwriteCommands)
+	route("GET /applications/{application}/guilds/{guild}/commands", s.getCommands)
+	route("PUT /applications/{application}/guilds/{guild}/commands", s.overwriteCommands)
+	route("POST /applications/{application}/guilds/{guild}/commands", s.upsertCommand)
+	route("PATCH /applications/{application}/guilds/{guild}/commands/{command}", s.editCommand)
+	route("DELETE /applications/{application}/guilds/{guild}/commands/{command}", s.deleteCommand)
+	mux.HandleFunc("/", s.handle(func(r *http.Request, body payload) (int, any) { return http.StatusNotFound, nil }))
+
+	mux.HandleFunc("GET /attachments/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		content, ok := s.files[r.URL.Path]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Attach makes the tempest client send its REST requests to the fake server instead of Discord
+func (s *Server) Attach(client *tempest.BaseClient) {
+	client.Rest.HTTPClient.Transport = s.Transport()
+}
+
+// Transport returns an http.RoundTripper sending requests for the Discord API to the fake server
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return roundTripper(func(r *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(r.URL.String(), tempest.DISCORD_API_URL) {
+			r = r.Clone(r.Context())
+			r.URL.Scheme = target.Scheme
+			r.URL.Host = target.Host
+			r.Host = target.Host
+		}
+
+		return s.Client().Transport.RoundTrip(r)
+	})
+}
+
+type roundTripper func(r *http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Wrap a route handler returning a status code and a value to encode as JSON.
+// Requests are recorded and handled one at a time, like the state they modify.
+func (s *Server) handle(handler func(r *http.Request, body payload) (int, any)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := readPayload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   path,
+			Query:  r.URL.Query(),
+			Body:   body.json,
+		})
+		status, failed := s.failures[r.Method+" "+path]
+		var value any
+		if !failed {
+			status, value = handler(r, body)
+		}
+		s.notify()
+		s.mu.Unlock()
+
+		switch {
+		case status >= http.StatusBadRequest && value == nil:
+			// Shaped like Discord's errors
+			value = map[string]any{"message": fmt.Sprintf("%d: %s", status, http.StatusText(status)), "code": 0}
+		case status == http.StatusNoContent:
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("Content-Type", tempest.CONTENT_TYPE_JSON)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(value)
+	}
+}
+
+// Read the JSON payload of a request, and the files of multipart requests
+func readPayload(r *http.Request) (payload, error) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		body, err := io.ReadAll(r.Body)
+		return payload{json: body}, err
+	}
+
+	var p payload
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return p, nil
+		} else if err != nil {
+			return payload{}, err
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return payload{}, err
+		}
+
+		if part.FormName() == "payload_json" {
+			p.json = content
+		} else {
+			p.files = append(p.files, file{name: part.FileName(), content: content})
+		}
+	}
+}
+
+// Wake up the callers of Wait. Must be called with mu held.
+func (s *Server) notify() {
+	s.version++
+	s.changed.Broadcast()
+}
+
+// Return a new snowflake created now. Must be called with mu held.
+func (s *Server) newID() tempest.Snowflake {
+	id := tempest.Snowflake(time.Now().UnixMilli()-tempest.DISCORD_EPOCH) << 22
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+
+	return id
+}
+
+// Parse a snowflake path parameter, returning 0 if it is not a valid snowflake
+func pathID(r *http.Request, name string) tempest.Snowflake {
+	id, _ := tempest.StringToSnowflake(r.PathValue(name))
+	return id
+}
+
+func (s *Server) getChannel(r *http.Request, body payload) (int, any) {
+	channel, ok := s.channels[pathID(r, "channel")]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	return http.StatusOK, channel
+}
+
+func (s *Server) modifyChannel(r *http.Request, body payload) (int, any) {
+	channel, ok := s.channels[pathID(r, "channel")]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	var params struct {
+		Name     *string `json:"name"`
+		Archived *bool   `json:"archived"`
+		Locked   *bool   `json:"locked"`
+	}
+	err := json.Unmarshal(body.json, &params)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+
+	if params.Name != nil {
+		channel.Name = *params.Name
+	}
+	if channel.ThreadMetadata != nil {
+		metadata := *channel.ThreadMetadata
+		if params.Archived != nil {
+			metadata.Archived = *params.Archived
+		}
+		if params.Locked != nil {
+			metadata.Locked = *params.Locked
+		}
+		channel.ThreadMetadata = &metadata
+	}
+	s.channels[channel.ID] = channel
+
+	return http.StatusOK, channel
+}
+
+func (s *Server) deleteChannel(r *http.Request, body payload) (int, any) {
+	id := pathID(r, "channel")
+	channel, ok := s.channels[id]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	delete(s.channels, id)
+	delete(s.messages, id)
+	delete(s.threadMembers, id)
+	delete(s.permissions, id)
+
+	return http.StatusOK, channel
+}
+
+func (s *Server) getMessages(r *http.Request, body payload) (int, any) {
+	id := pathID(r, "channel")
+	if _, ok := s.channels[id]; !ok {
+		return http.StatusNotFound, nil
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	before, _ := tempest.StringToSnowflake(r.URL.Query().Get("before"))
+
+	// Newest first, like Discord
+	page := []tempest.Message{}
+	messages := s.messages[id]
+	for i := len(messages) - 1; i >= 0 && len(page) < limit; i-- {
+		if before == 0 || messages[i].ID < before {
+			page = append(page, messages[i])
+		}
+	}
+
+	return http.StatusOK, page
+}
+
+func (s *Server) getMessage(r *http.Request, body payload) (int, any) {
+	i, ok := s.findMessage(pathID(r, "channel"), pathID(r, "message"))
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	return http.StatusOK, s.messages[pathID(r, "channel")][i]
+}
+
+// Return the index of a message in its channel
+func (s *Server) findMessage(channelID, messageID tempest.Snowflake) (int, bool) {
+	i := slices.IndexFunc(s.messages[channelID], func(m tempest.Message) bool { return m.ID == messageID })
+	return i, i >= 0
+}
+
+func (s *Server) createMessage(r *http.Request, body payload) (int, any) {
+	id := pathID(r, "channel")
+	if _, ok := s.channels[id]; !ok {
+		return http.StatusNotFound, nil
+	}
+
+	var msg tempest.Message
+	err := json.Unmarshal(body.json, &msg)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+
+	author := s.BotUser
+	msg.Author = &author
+	msg.Attachments = nil
+	for _, f := range body.files {
+		attachmentID := s.newID()
+		path := fmt.Sprintf("/attachments/%d/%d/%s", id, attachmentID, url.PathEscape(f.name))
+		s.files[path] = f.content
+		msg.Attachments = append(msg.Attachments, tempest.Attachment{
+			ID:       attachmentID,
+			FileName: f.name,
+			Size:     uint64(len(f.content)),
+			URL:      s.URL + path,
+			ProxyURL: s.URL + path,
+		})
+	}
+
+	return http.StatusOK, s.addMessage(id, msg)
+}
+
+func (s *Server) editMessage(r *http.Request, body payload) (int, any) {
+	channelID := pathID(r, "channel")
+	i, ok := s.findMessage(channelID, pathID(r, "message"))
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	msg := s.messages[channelID][i]
+	err := json.Unmarshal(body.json, &msg)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	now := time.Now()
+	msg.EditedTimestamp = &now
+	s.messages[channelID][i] = msg
+
+	return http.StatusOK, msg
+}
+
+func (s *Server) deleteMessage(r *http.Request, body payload) (int, any) {
+	channelID := pathID(r, "channel")
+	i, ok := s.findMessage(channelID, pathID(r, "message"))
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	s.messages[channelID] = slices.Delete(s.messages[channelID], i, i+1)
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) createThread(r *http.Request, body payload) (int, any) {
+	parent, ok := s.channels[pathID(r, "channel")]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	var params types.CreateThreadWithoutMessageParams
+	err := json.Unmarshal(body.json, &params)
+	if err != nil || params.Name == "" {
+		return http.StatusBadRequest, nil
+	}
+
+	// Threads created without a message are private unless asked otherwise
+	threadType := tempest.GUILD_PRIVATE_THREAD_CHANNEL_TYPE
+	if params.Type != 0 {
+		threadType = tempest.ChannelType(params.Type)
+	}
+
+	thread := types.Channel{
+		ID:       s.newID(),
+		Type:     threadType,
+		GuildID:  parent.GuildID,
+		Name:     params.Name,
+		ParentID: parent.ID,
+		ThreadMetadata: &types.ThreadMetadata{
+			AutoArchiveDuration: params.AutoArchiveDuration,
+			Invitable:           params.Invitable,
+			CreateTimestamp:     time.Now(),
+		},
+	}
+	s.channels[thread.ID] = thread
+	s.threadMembers[thread.ID] = []tempest.Snowflake{s.BotUser.ID}
+
+	return http.StatusCreated, thread
+}
+
+func (s *Server) getThreadMember(r *http.Request, body payload) (int, any) {
+	threadID, userID := pathID(r, "channel"), pathID(r, "user")
+	if !slices.Contains(s.threadMembers[threadID], userID) {
+		return http.StatusNotFound, nil
+	}
+
+	return http.StatusOK, map[string]any{"id": threadID, "user_id": userID}
+}
+
+func (s *Server) addThreadMember(r *http.Request, body payload) (int, any) {
+	threadID, userID := pathID(r, "channel"), pathID(r, "user")
+	if _, ok := s.channels[threadID]; !ok {
+		return http.StatusNotFound, nil
+	}
+
+	if !slices.Contains(s.threadMembers[threadID], userID) {
+		s.threadMembers[threadID] = append(s.threadMembers[threadID], userID)
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) removeThreadMember(r *http.Request, body payload) (int, any) {
+	threadID, userID := pathID(r, "channel"), pathID(r, "user")
+	s.threadMembers[threadID] = slices.DeleteFunc(s.threadMembers[threadID], func(id tempest.Snowflake) bool { return id == userID })
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) setPermissions(r *http.Request, body payload) (int, any) {
+	channelID := pathID(r, "channel")
+	if _, ok := s.channels[channelID]; !ok {
+		return http.StatusNotFound, nil
+	}
+
+	if s.permissions[channelID] == nil {
+		s.permissions[channelID] = map[tempest.Snowflake]json.RawMessage{}
+	}
+	s.permissions[channelID][pathID(r, "user")] = json.RawMessage(body.json)
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) deletePermissions(r *http.Request, body payload) (int, any) {
+	delete(s.permissions[pathID(r, "channel")], pathID(r, "user"))
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) getUser(r *http.Request, body payload) (int, any) {
+	user, ok := s.users[pathID(r, "user")]
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	return http.StatusOK, user
+}
+
+// Record a response to an interaction sent after the initial one: a deferred modal response, a follow-up
+// or an edit of the original response
+func (s *Server) interactionResponse(r *http.Request, body payload) (int, any) {
+	token := r.PathValue("token")
+	s.responses[token] = append(s.responses[token], json.RawMessage(body.json))
+
+	if r.Method != http.MethodPost || strings.HasPrefix(r.URL.Path, apiPrefix+"/interactions/") {
+		return http.StatusNoContent, nil
+	}
+
+	// Follow-ups are messages, so reply with one
+	var msg tempest.Message
+	json.Unmarshal(body.json, &msg)
+	msg.ID = s.newID()
+	author := s.BotUser
+	msg.Author = &author
+
+	return http.StatusOK, msg
+}
+
+// Return the index of the command with the same name and type as `command` in `commands`, or -1.
+// Like Discord, commands without a type are chat input commands.
+func findCommand(commands []map[string]any, command map[string]any) int {
+	commandType := func(c map[string]any) any { return cmp.Or(c["type"], any(float64(tempest.CHAT_INPUT_COMMAND_TYPE))) }
+	return slices.IndexFunc(commands, func(c map[string]any) bool {
+		return c["name"] == command["name"] && commandType(c) == commandType(command)
+	})
+}
+
+// Return the index of the command with the ID in the request path in `commands`, or -1
+func findCommandByID(r *http.Request, commands []map[string]any) int {
+	id := r.PathValue("command")
+	return slices.IndexFunc(commands, func(c map[string]any) bool { return c["id"] == id })
+}
+
+func (s *Server) getCommands(r *http.Request, body payload) (int, any) {
+	commands := s.commands[pathID(r, "guild")]
+	if commands == nil {
+		commands = []map[string]any{}
+	}
+
+	return http.StatusOK, commands
+}
+
+// Replace every command, keeping the IDs of the commands that already existed like Discord does
+func (s *Server) overwriteCommands(r *http.Request, body payload) (int, any) {
+	var commands []map[string]any
+	err := json.Unmarshal(body.json, &commands)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+
+	guildID := pathID(r, "guild")
+	for _, command := range commands {
+		command["id"] = s.newID().String()
+		if i := findCommand(s.commands[guildID], command); i != -1 {
+			command["id"] = s.commands[guildID][i]["id"]
+		}
+	}
+
+	s.commands[guildID] = commands
+	return http.StatusOK, commands
+}
+
+// Create a command, or replace the one with the same name
+func (s *Server) upsertCommand(r *http.Request, body payload) (int, any) {
+	var command map[string]any
+	err := json.Unmarshal(body.json, &command)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+
+	guildID := pathID(r, "guild")
+	if i := findCommand(s.commands[guildID], command); i != -1 {
+		command["id"] = s.commands[guildID][i]["id"]
+		s.commands[guildID][i] = command
+		return http.StatusOK, command
+	}
+
+	command["id"] = s.newID().String()
+	s.commands[guildID] = append(s.commands[guildID], command)
+	return http.StatusCreated, command
+}
+
+func (s *Server) editCommand(r *http.Request, body payload) (int, any) {
+	var changes map[string]any
+	err := json.Unmarshal(body.json, &changes)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+
+	commands := s.commands[pathID(r, "guild")]
+	i := findCommandByID(r, commands)
+	if i == -1 {
+		return http.StatusNotFound, nil
+	}
+
+	for field, value := range changes {
+		if field != "id" {
+			commands[i][field] = value
+		}
+	}
+	return http.StatusOK, commands[i]
+}
+
+func (s *Server) deleteCommand(r *http.Request, body payload) (int, any) {
+	guildID := pathID(r, "guild")
+	i := findCommandByID(r, s.commands[guildID])
+	if i == -1 {
+		return http.StatusNotFound, nil
+	}
+
+	s.commands[guildID] = slices.Delete(s.commands[guildID], i, i+1)
+	return http.StatusNoContent, nil
+}
+
+// Append a message to a channel and make it the channel's last message. Must be called with mu held.
+func (s *Server) addMessage(channelID tempest.Snowflake, msg tempest.Message) tempest.Message {
+	now := time.Now()
+	msg.ID = s.newID()
+	msg.ChannelID = channelID
+	msg.Timestamp = &now
+	s.messages[channelID] = append(s.messages[channelID], msg)
+
+	channel := s.channels[channelID]
+	channel.LastMessageID = &msg.ID
+	s.channels[channelID] = channel
+
+	return msg
+}
+
+// AddChannel creates or replaces a channel, e.g. the ticket channel threads are created in
+func (s *Server) AddChannel(channel types.Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[channel.ID] = channel
+}
+
+// AddUser makes a user known to the fake server, so that it can be fetched
+func (s *Server) AddUser(user tempest.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = user
+}
+
+// SendMessage posts a message in a channel as `author`, e.g. the user replying in their ticket
+func (s *Server) SendMessage(channelID tempest.Snowflake, author tempest.User, content string) tempest.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.addMessage(channelID, tempest.Message{Author: &author, Content: content})
+	s.notify()
+	return msg
+}
+
+// Channel returns a channel, and whether it exists
+func (s *Server) Channel(id tempest.Snowflake) (types.Channel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, ok := s.channels[id]
+	return channel, ok
+}
+
+// Threads returns the threads created in a channel, oldest first
+func (s *Server) Threads(parentID tempest.Snowflake) []types.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var threads []types.Channel
+	for _, channel := range s.channels {
+		if channel.ParentID == parentID && channel.ThreadMetadata != nil {
+			threads = append(threads, channel)
+		}
+	}
+	slices.SortFunc(threads, func(a, b types.Channel) int { return cmp.Compare(a.ID, b.ID) })
+
+	return threads
+}
+
+// Messages returns the messages of a channel, oldest first
+func (s *Server) Messages(channelID tempest.Snowflake) []tempest.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages[channelID])
+}
+
+// File returns the content of an attachment, from its URL
+func (s *Server) File(attachmentURL string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, ok := s.files[strings.TrimPrefix(attachmentURL, s.URL)]
+	return content, ok
+}
+
+// ThreadMembers returns the IDs of the members of a thread
+func (s *Server) ThreadMembers(threadID tempest.Snowflake) []tempest.Snowflake {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.threadMembers[threadID])
+}
+
+// Permissions returns the permission overwrite set for a user in a channel, and whether there is one
+func (s *Server) Permissions(channelID, userID tempest.Snowflake) (json.RawMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overwrite, ok := s.permissions[channelID][userID]
+	return overwrite, ok
+}
+
+// InteractionResponses returns the payloads sent through the REST API in response to an interaction, in order.
+// The initial response is not included, as tempest returns it in the HTTP response instead.
+func (s *Server) InteractionResponses(token string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.responses[token])
+}
+
+// Command is an application command registered with the fake server
+type Command struct {
+	ID          tempest.Snowflake       `json:"id"`
+	Type        tempest.CommandType     `json:"type"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Options     []tempest.CommandOption `json:"options"`
+}
+
+// Commands returns the commands registered in a guild (0 for global commands)
+func (s *Server) Commands(guildID tempest.Snowflake) []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, _ := json.Marshal(s.commands[guildID])
+	var commands []Command
+	json.Unmarshal(raw, &commands)
+	return commands
+}
+
+// Fail makes the server answer the requests to `path` (relative to the API's base URL, e.g. "/channels/123") with
+// `method` with the error `status` instead of handling them, as Discord does when the bot is missing a permission
+func (s *Server) Fail(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method+" "+path] = status
+}
+
+// Requests returns every request the server received, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// Wait blocks until `done` returns true or `timeout` elapses, checking it again after every request.
+// Tempest runs handlers in the background, so requests made after the initial response need to be waited for.
+// Returns whether `done` returned true.
+func (s *Server) Wait(timeout time.Duration, done func() bool) bool {
+	expired := false
+	timer := time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		expired = true
+		s.changed.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		version := s.version
+		s.mu.Unlock()
+
+		if done() {
+			return true
+		}
+
+		s.mu.Lock()
+		for s.version == version && !expired {
+			s.changed.Wait()
+		}
+		stop := expired
+		s.mu.Unlock()
+
+		if stop {
+			return done()
+		}
+	}
+}
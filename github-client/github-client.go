@@ -9,55 +9,48 @@ package githubClient
 
 import (
 	"context"
-	"log"
-	"os"
-	"strconv"
+	"fmt"
+
+	"github.com/pagefaultgames/ticketune/config"
 
 	"github.com/google/go-github/v74/github"
 	"github.com/jferrl/go-githubauth"
 	"golang.org/x/oauth2"
 )
 
-var Client *github.Client
+// Client is a GitHub client authenticated as an installation of the GitHub App
+type Client struct {
+	*github.Client
+	tokens oauth2.TokenSource // The installation tokens the client authenticates with
+}
 
+// Create a GitHub client authenticated as the installation of the GitHub App in `cfg`
 /* Based off of https://github.com/google/go-github/blob/f137c94931a722223df8cc7581a2a3e953ad8d63/README.md */
-func init() {
-	log.Print("Initializing GitHub client...")
-
-	privateKey := []byte(os.Getenv("TICKETUNE_GITHUB_BOT_PKEY"))
-	if len(privateKey) == 0 {
-		log.Fatal("TICKETUNE_GITHUB_BOT_PKEY environment variable not set")
-	}
-
-	clientId := os.Getenv("TICKETUNE_GITHUB_CLIENT_ID")
-	if clientId == "" {
-		log.Fatal("TICKETUNE_GITHUB_CLIENT_ID environment variable not set")
-	}
-
-	appTokenSource, err := githubauth.NewApplicationTokenSource(clientId, privateKey)
+func New(cfg config.GitHub) (*Client, error) {
+	appTokenSource, err := githubauth.NewApplicationTokenSource(cfg.ClientID, []byte(cfg.PrivateKey))
 	if err != nil {
-		log.Fatal("failed to create GitHub App token source: " + err.Error())
+		return nil, fmt.Errorf("failed to create GitHub App token source: %w", err)
 	}
 
-	installationIdStr, err := strconv.ParseInt(os.Getenv("TICKETUNE_INSTALL_ID"), 10, 64)
-	if err != nil {
-		log.Fatal("TICKETUNE_INSTALL_ID environment variable not set or not an integer")
-	}
+	// Shared with the HTTP client, so that checking the token does not request a new one every time
+	tokens := oauth2.ReuseTokenSource(nil, githubauth.NewInstallationTokenSource(cfg.InstallationID, appTokenSource))
 
-	installationTokenSource := githubauth.NewInstallationTokenSource(installationIdStr, appTokenSource)
+	httpClient := oauth2.NewClient(context.Background(), tokens)
 
-	httpClient := oauth2.NewClient(context.Background(), installationTokenSource)
-
-	Client = github.NewClient(httpClient)
+	return &Client{Client: github.NewClient(httpClient), tokens: tokens}, nil
+}
 
-	log.Print("GitHub client initialized.")
+// CheckToken returns an error if no installation token can be obtained, e.g. because the app was uninstalled
+func (c *Client) CheckToken() error {
+	_, err := c.tokens.Token()
+	return err
 }
 
 // Get the installation ID for the github app installation on the org
 // This only needs to be done once per install of the app, so once the github app has been
 // created and installed, it never needs to be re-run (unless the app is uninstalled and reinstalled).
 // In such a case, the code should be manually invoked to print the installation ID, which should then be
-// set in the environment variable `TICKETUNE_INSTALL_ID`
+// set as github.installation_id in the configuration file (or the environment variable `TICKETUNE_INSTALL_ID`)
 /*
 func getInstallId(appTokenSource oauth2.TokenSource) int64 {
 	// idResponse field, see
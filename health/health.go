@@ -0,0 +1,58 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Liveness and readiness endpoints, for the orchestrator running the bot
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How long every readiness check may take
+const checkTimeout = 5 * time.Second
+
+// Check is something the bot depends on, and a function returning an error if it is not working
+type Check struct {
+	Name  string                          // Shown in the body of /readyz
+	Check func(ctx context.Context) error // Must return before `ctx` is done
+}
+
+// Live answers /healthz: the process is up and serving HTTP requests
+func Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// Ready returns the handler of /readyz, which runs every check and answers 503 Service Unavailable if one fails.
+// The body lists the result of each check.
+func Ready(checks ...Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		var body strings.Builder
+		status := http.StatusOK
+		for _, c := range checks {
+			err := c.Check(ctx)
+			if err != nil {
+				status = http.StatusServiceUnavailable
+				fmt.Fprintf(&body, "%s: %v\n", c.Name, err)
+			} else {
+				fmt.Fprintf(&body, "%s: ok\n", c.Name)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body.String())
+	}
+}
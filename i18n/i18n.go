@@ -0,0 +1,107 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Translations of the messages users see, selected by their Discord locale
+
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/amatsagu/tempest"
+)
+
+// The locale messages are written in, used when no translation exists for a user's locale
+const DEFAULT_LOCALE = tempest.ENGLISH_US_LANGUAGE
+
+// Every locale Discord supports, with its name in that language
+// https://discord.com/developers/docs/reference#locales
+var LocaleNames = map[tempest.Language]string{
+	"id":                            "Bahasa Indonesia",
+	tempest.DANISH_LANGUAGE:         "Dansk",
+	tempest.GERMAN_LANGUAGE:         "Deutsch",
+	tempest.ENGLISH_UK_LANGUAGE:     "English, UK",
+	tempest.ENGLISH_US_LANGUAGE:     "English, US",
+	tempest.SPANISH_LANGUAGE:        "Español",
+	"es-419":                        "Español, LATAM",
+	tempest.FRENCH_LANGUAGE:         "Français",
+	tempest.CROATIAN_LANGUAGE:       "Hrvatski",
+	tempest.ITALIAN_LANGUAGE:        "Italiano",
+	tempest.LITHUANIAN_LANGUAGE:     "Lietuviškai",
+	tempest.HUNGARIAN_LANGUAGE:      "Magyar",
+	tempest.DUTCH_LANGUAGE:          "Nederlands",
+	tempest.NORWEGIAN_LANGUAGE:      "Norsk",
+	tempest.POLISH_LANGUAGE:         "Polski",
+	tempest.PORTUGUESE_BR_LANGUAGE:  "Português do Brasil",
+	tempest.ROMANIAN_LANGUAGE:       "Română",
+	tempest.FINNISH_LANGUAGE:        "Suomi",
+	tempest.SWEDISH_LANGUAGE:        "Svenska",
+	tempest.VIETNAMESE_LANGUAGE:     "Tiếng Việt",
+	tempest.TURKISH_LANGUAGE:        "Türkçe",
+	tempest.CHECH_LANGUAGE:          "Čeština",
+	tempest.GREEK_LANGUAGE:          "Ελληνικά",
+	tempest.BULGARIAN_LANGUAGE:      "български",
+	tempest.RUSSIAN_LANGUAGE:        "Русский",
+	tempest.UKRAINIAN_LANGUAGE:      "Українська",
+	tempest.HINDI_LANGUAGE:          "हिन्दी",
+	tempest.THAI_LANGUAGE:           "ไทย",
+	tempest.CHINESE_CHINA_LANGUAGE:  "中文",
+	tempest.JAPANESE_LANGUAGE:       "日本語",
+	tempest.CHINESE_TAIWAN_LANGUAGE: "繁體中文",
+	tempest.KOREAN_LANGUAGE:         "한국어",
+}
+
+// IsLocale returns whether `locale` is one of the locales supported by Discord
+func IsLocale(locale tempest.Language) bool {
+	_, ok := LocaleNames[locale]
+	return ok
+}
+
+// Return the language part of a locale, e.g. "pt" for "pt-BR"
+func language(locale tempest.Language) string {
+	lang, _, _ := strings.Cut(string(locale), "-")
+	return lang
+}
+
+// Fallbacks returns the locales to look for a translation in, in order of preference:
+// `locale` itself, the other variants of the same language (e.g. es-ES for es-419), then DEFAULT_LOCALE.
+func Fallbacks(locale tempest.Language) []tempest.Language {
+	fallbacks := []tempest.Language{}
+	if IsLocale(locale) {
+		fallbacks = append(fallbacks, locale)
+	}
+
+	var variants []tempest.Language
+	for l := range LocaleNames {
+		if l != locale && language(l) == language(locale) {
+			variants = append(variants, l)
+		}
+	}
+	slices.Sort(variants)
+	fallbacks = append(fallbacks, variants...)
+
+	if !slices.Contains(fallbacks, DEFAULT_LOCALE) {
+		fallbacks = append(fallbacks, DEFAULT_LOCALE)
+	}
+
+	return fallbacks
+}
+
+// Message returns the translation of a message in `locale`, formatted with `args` as by fmt.Sprintf.
+// If the message is not translated to `locale`, the closest available translation is used.
+func Message(locale tempest.Language, id MessageID, args ...any) string {
+	for _, l := range Fallbacks(locale) {
+		if format, ok := messages[l][id]; ok {
+			return fmt.Sprintf(format, args...)
+		}
+	}
+
+	// Every message has a DEFAULT_LOCALE translation, so this means the ID itself is wrong
+	return string(id)
+}
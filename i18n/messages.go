@@ -0,0 +1,234 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package i18n
+
+import "github.com/amatsagu/tempest"
+
+// MessageID identifies a message shown to users opening or waiting on a ticket
+type MessageID string
+
+const (
+	TICKET_INSTRUCTIONS                   MessageID = "ticket-instructions" // %s: mention of the user
+	TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION MessageID = "ticket-instructions-image-description"
+	TICKET_CREATED                        MessageID = "ticket-created"              // %d: thread ID
+	TICKET_ALREADY_EXISTS                 MessageID = "ticket-already-exists"       // %d: thread ID
+	COULD_NOT_CREATE_THREAD               MessageID = "could-not-create-thread"     // %d: troubleshooting channel ID
+	COULD_NOT_ADD_TO_THREAD               MessageID = "could-not-add-to-thread"     // %d: troubleshooting channel ID
+	COULD_NOT_SEND_INSTRUCTIONS           MessageID = "could-not-send-instructions" // %d: troubleshooting channel ID
+	COULD_NOT_GET_USER_ID                 MessageID = "could-not-get-user-id"       // %d: troubleshooting channel ID
+	GREETING                              MessageID = "greeting"                    // %s: mention of the user
+	INACTIVITY_REMINDER                   MessageID = "inactivity-reminder"         // %d: user ID
+	INACTIVITY_CLOSE_WARNING              MessageID = "inactivity-close-warning"    // %d: Unix time the ticket will be closed at
+	TICKET_BANNED                         MessageID = "ticket-banned"
+	TICKET_BAN_ENDS                       MessageID = "ticket-ban-ends"     // %d: Unix time the ban ends at
+	TICKET_COOLDOWN                       MessageID = "ticket-cooldown"     // %d: Unix time the user may open a ticket again
+	TICKET_RATE_LIMITED                   MessageID = "ticket-rate-limited" // %d: Unix time tickets may be opened again
+	INTAKE_TITLE                          MessageID = "intake-title"
+	INTAKE_USERNAME                       MessageID = "intake-username"
+	INTAKE_USERNAME_HINT                  MessageID = "intake-username-hint"
+	INTAKE_ACCOUNT_CREATED                MessageID = "intake-account-created"
+	INTAKE_ACCOUNT_CREATED_HINT           MessageID = "intake-account-created-hint"
+	INTAKE_LAST_PLAYED                    MessageID = "intake-last-played"
+	INTAKE_LAST_PLAYED_HINT               MessageID = "intake-last-played-hint"
+	INTAKE_PLATFORM                       MessageID = "intake-platform"
+	INTAKE_PLATFORM_HINT                  MessageID = "intake-platform-hint"
+)
+
+// Translations of every message, by locale. Every message must be translated to DEFAULT_LOCALE.
+var messages = map[tempest.Language]map[MessageID]string{
+	tempest.ENGLISH_US_LANGUAGE: {
+		TICKET_INSTRUCTIONS: "### Hello %s!\n" +
+			"Please provide a screenshot of the login page __with the usernames panel open or the error code it might display__.\n" +
+			"You need to __click on the gear in the top left corner__ (see attached image for where to find that)!\n" +
+			"**Please keep in mind that we are real people volunteering our time, so please don't ping us over and over. " +
+			"When someone is free, they'll reach out to help you, but until then, please be patient and wait until " +
+			"we get back to you.**\n\n" +
+			"This process will link your PokéRogue account with the Discord account you used to open this ticket, allowing you to log in without " +
+			"needing your password.\n" +
+			"We have __no way__ to access, check, change, or reset your password, however once you are logged in you are able to change it in the menu yourself.\n" +
+			"Also, **NEVER** give out personal details such as passwords anywhere and to anyone, including in these threads.",
+		TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION: "Image showing the location of the usernames panel",
+		TICKET_CREATED:                        "A new ticket has been created: <#%d>",
+		TICKET_ALREADY_EXISTS:                 "I found an existing ticket, try using this: <#%d>",
+		COULD_NOT_CREATE_THREAD: "I was unable to create your support ticket. Please try again.\n" +
+			"If this issue persists, please reach out to someone in <#%d>.",
+		COULD_NOT_ADD_TO_THREAD: "I created support thread, but something went wrong while trying to give you access to it. " +
+			"Please reach out to someone in <#%d> for help, and mention that I was unable to give you access to your password reset ticket.",
+		COULD_NOT_SEND_INSTRUCTIONS: "I created your support ticket and added you to it, " +
+			"but something went wrong while trying to send the instructions. Please reach out to someone in <#%d>, and mention that I could not send the instructions.",
+		COULD_NOT_GET_USER_ID:       "Something went wrong, I couldn't get your user ID. Please try again, and if the issue persists, reach out to someone in <#%d>.",
+		GREETING:                    "Hi %s!",
+		INACTIVITY_REMINDER:         "Hi <@%d>! We haven't heard back from you in a while. Please reply here if you still need help.",
+		INACTIVITY_CLOSE_WARNING:    "Otherwise, this ticket will be closed automatically <t:%d:R>.",
+		TICKET_BANNED:               "You are not allowed to open tickets.",
+		TICKET_BAN_ENDS:             "You can open tickets again <t:%d:R>.",
+		TICKET_COOLDOWN:             "You opened a ticket recently. You can open another one <t:%d:R>.",
+		TICKET_RATE_LIMITED:         "A lot of tickets are being opened right now, and our helpers need time to get to them. Please try again <t:%d:R>.",
+		INTAKE_TITLE:                "Open a password ticket",
+		INTAKE_USERNAME:             "Username",
+		INTAKE_USERNAME_HINT:        "The PokéRogue username you think you used",
+		INTAKE_ACCOUNT_CREATED:      "Account creation date",
+		INTAKE_ACCOUNT_CREATED_HINT: "Roughly when you created your account, e.g. \"spring 2024\"",
+		INTAKE_LAST_PLAYED:          "Last played",
+		INTAKE_LAST_PLAYED_HINT:     "Roughly when you last played, e.g. \"last week\"",
+		INTAKE_PLATFORM:             "Platform and browser",
+		INTAKE_PLATFORM_HINT:        "What you play on, e.g. \"Android, Chrome\" or \"Windows, Firefox\"",
+	},
+	tempest.FRENCH_LANGUAGE: {
+		TICKET_INSTRUCTIONS: "### Bonjour %s !\n" +
+			"Merci de fournir une capture d'écran de la page de connexion __avec le panneau des noms d'utilisateur ouvert, ou le code d'erreur qui s'affiche__.\n" +
+			"Il faut __cliquer sur l'engrenage en haut à gauche__ (voir l'image jointe pour le trouver) !\n" +
+			"**Gardez à l'esprit que nous sommes de vraies personnes qui donnent de leur temps bénévolement, merci donc de ne pas nous mentionner encore et encore. " +
+			"Dès que quelqu'un sera disponible, il viendra vous aider ; en attendant, merci de patienter jusqu'à ce que " +
+			"nous revenions vers vous.**\n\n" +
+			"Cette procédure va lier votre compte PokéRogue au compte Discord avec lequel vous avez ouvert ce ticket, ce qui vous permettra de vous connecter sans " +
+			"votre mot de passe.\n" +
+			"Nous n'avons __aucun moyen__ de consulter, vérifier, modifier ou réinitialiser votre mot de passe, mais une fois connecté vous pourrez le changer vous-même dans le menu.\n" +
+			"Enfin, ne communiquez **JAMAIS** d'informations personnelles comme vos mots de passe, nulle part et à personne, y compris dans ces fils.",
+		TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION: "Image montrant où se trouve le panneau des noms d'utilisateur",
+		TICKET_CREATED:                        "Un nouveau ticket a été créé : <#%d>",
+		TICKET_ALREADY_EXISTS:                 "Vous avez déjà un ticket ouvert, utilisez celui-ci : <#%d>",
+		COULD_NOT_CREATE_THREAD: "Je n'ai pas pu créer votre ticket. Merci de réessayer.\n" +
+			"Si le problème persiste, demandez de l'aide dans <#%d>.",
+		COULD_NOT_ADD_TO_THREAD: "J'ai créé votre ticket, mais quelque chose s'est mal passé en essayant de vous y donner accès. " +
+			"Demandez de l'aide dans <#%d>, en précisant que je n'ai pas pu vous donner accès à votre ticket de mot de passe.",
+		COULD_NOT_SEND_INSTRUCTIONS: "J'ai créé votre ticket et vous y ai ajouté, " +
+			"mais quelque chose s'est mal passé en envoyant les instructions. Demandez de l'aide dans <#%d>, en précisant que je n'ai pas pu envoyer les instructions.",
+		COULD_NOT_GET_USER_ID:       "Quelque chose s'est mal passé, je n'ai pas pu récupérer votre identifiant. Merci de réessayer, et si le problème persiste, demandez de l'aide dans <#%d>.",
+		GREETING:                    "Bonjour %s !",
+		INACTIVITY_REMINDER:         "Bonjour <@%d> ! Nous n'avons pas eu de nouvelles de votre part depuis un moment. Répondez ici si vous avez encore besoin d'aide.",
+		INACTIVITY_CLOSE_WARNING:    "Sinon, ce ticket sera fermé automatiquement <t:%d:R>.",
+		TICKET_BANNED:               "Vous n'êtes pas autorisé à ouvrir des tickets.",
+		TICKET_BAN_ENDS:             "Vous pourrez à nouveau ouvrir des tickets <t:%d:R>.",
+		TICKET_COOLDOWN:             "Vous avez ouvert un ticket récemment. Vous pourrez en ouvrir un autre <t:%d:R>.",
+		TICKET_RATE_LIMITED:         "Beaucoup de tickets sont ouverts en ce moment, et nos helpers ont besoin de temps pour s'en occuper. Veuillez réessayer <t:%d:R>.",
+		INTAKE_TITLE:                "Ouvrir un ticket de mot de passe",
+		INTAKE_USERNAME:             "Nom d'utilisateur",
+		INTAKE_USERNAME_HINT:        "Le nom d'utilisateur PokéRogue que vous pensez avoir utilisé",
+		INTAKE_ACCOUNT_CREATED:      "Date de création du compte",
+		INTAKE_ACCOUNT_CREATED_HINT: "Environ quand vous avez créé votre compte, ex. \"printemps 2024\"",
+		INTAKE_LAST_PLAYED:          "Dernière partie",
+		INTAKE_LAST_PLAYED_HINT:     "Environ quand vous avez joué pour la dernière fois, ex. \"la semaine dernière\"",
+		INTAKE_PLATFORM:             "Plateforme et navigateur",
+		INTAKE_PLATFORM_HINT:        "Sur quoi vous jouez, ex. \"Android, Chrome\" ou \"Windows, Firefox\"",
+	},
+	tempest.GERMAN_LANGUAGE: {
+		TICKET_INSTRUCTIONS: "### Hallo %s!\n" +
+			"Bitte schicke einen Screenshot der Anmeldeseite __mit geöffnetem Benutzernamen-Fenster oder dem angezeigten Fehlercode__.\n" +
+			"Dazu musst du __auf das Zahnrad oben links klicken__ (im angehängten Bild siehst du, wo es ist)!\n" +
+			"**Denk bitte daran, dass wir echte Menschen sind, die ehrenamtlich helfen. Bitte pinge uns also nicht immer wieder an. " +
+			"Sobald jemand Zeit hat, meldet sich jemand bei dir. Bis dahin hab bitte etwas Geduld und warte, bis " +
+			"wir uns bei dir melden.**\n\n" +
+			"Dabei wird dein PokéRogue-Konto mit dem Discord-Konto verknüpft, mit dem du dieses Ticket geöffnet hast, sodass du dich ohne " +
+			"Passwort anmelden kannst.\n" +
+			"Wir haben __keine Möglichkeit__, dein Passwort einzusehen, zu prüfen, zu ändern oder zurückzusetzen. Sobald du angemeldet bist, kannst du es aber selbst im Menü ändern.\n" +
+			"Und gib **NIEMALS** persönliche Daten wie Passwörter weiter, nirgendwo und an niemanden, auch nicht in diesen Threads.",
+		TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION: "Bild, das zeigt, wo das Benutzernamen-Fenster zu finden ist",
+		TICKET_CREATED:                        "Ein neues Ticket wurde erstellt: <#%d>",
+		TICKET_ALREADY_EXISTS:                 "Du hast bereits ein offenes Ticket, nutze bitte dieses: <#%d>",
+		COULD_NOT_CREATE_THREAD: "Ich konnte dein Support-Ticket nicht erstellen. Bitte versuche es erneut.\n" +
+			"Wenn das Problem weiterhin besteht, wende dich bitte an jemanden in <#%d>.",
+		COULD_NOT_ADD_TO_THREAD: "Ich habe dein Ticket erstellt, konnte dir aber keinen Zugriff darauf geben. " +
+			"Bitte wende dich an jemanden in <#%d> und erwähne, dass ich dir keinen Zugriff auf dein Passwort-Ticket geben konnte.",
+		COULD_NOT_SEND_INSTRUCTIONS: "Ich habe dein Ticket erstellt und dich hinzugefügt, " +
+			"konnte aber die Anleitung nicht senden. Bitte wende dich an jemanden in <#%d> und erwähne, dass ich die Anleitung nicht senden konnte.",
+		COULD_NOT_GET_USER_ID:       "Etwas ist schiefgelaufen, ich konnte deine Benutzer-ID nicht abrufen. Bitte versuche es erneut, und wenn das Problem weiterhin besteht, wende dich an jemanden in <#%d>.",
+		GREETING:                    "Hallo %s!",
+		INACTIVITY_REMINDER:         "Hallo <@%d>! Wir haben schon eine Weile nichts von dir gehört. Bitte antworte hier, wenn du noch Hilfe brauchst.",
+		INACTIVITY_CLOSE_WARNING:    "Andernfalls wird dieses Ticket <t:%d:R> automatisch geschlossen.",
+		TICKET_BANNED:               "Du darfst keine Tickets eröffnen.",
+		TICKET_BAN_ENDS:             "Du kannst <t:%d:R> wieder Tickets eröffnen.",
+		TICKET_COOLDOWN:             "Du hast vor Kurzem ein Ticket eröffnet. Du kannst <t:%d:R> ein weiteres eröffnen.",
+		TICKET_RATE_LIMITED:         "Gerade werden sehr viele Tickets eröffnet, und unsere Helfer brauchen Zeit, um sich darum zu kümmern. Bitte versuche es <t:%d:R> erneut.",
+		INTAKE_TITLE:                "Passwort-Ticket eröffnen",
+		INTAKE_USERNAME:             "Benutzername",
+		INTAKE_USERNAME_HINT:        "Der PokéRogue-Benutzername, den du vermutlich verwendet hast",
+		INTAKE_ACCOUNT_CREATED:      "Kontoerstellung",
+		INTAKE_ACCOUNT_CREATED_HINT: "Ungefähr wann du dein Konto erstellt hast, z. B. \"Frühling 2024\"",
+		INTAKE_LAST_PLAYED:          "Zuletzt gespielt",
+		INTAKE_LAST_PLAYED_HINT:     "Ungefähr wann du zuletzt gespielt hast, z. B. \"letzte Woche\"",
+		INTAKE_PLATFORM:             "Plattform und Browser",
+		INTAKE_PLATFORM_HINT:        "Worauf du spielst, z. B. \"Android, Chrome\" oder \"Windows, Firefox\"",
+	},
+	tempest.SPANISH_LANGUAGE: {
+		TICKET_INSTRUCTIONS: "### ¡Hola, %s!\n" +
+			"Por favor, envía una captura de pantalla de la página de inicio de sesión __con el panel de nombres de usuario abierto o el código de error que aparezca__.\n" +
+			"Tienes que __hacer clic en el engranaje de la esquina superior izquierda__ (mira la imagen adjunta para encontrarlo).\n" +
+			"**Recuerda que somos personas reales que dedicamos nuestro tiempo como voluntarios, así que por favor no nos menciones una y otra vez. " +
+			"Cuando alguien esté libre, se pondrá en contacto contigo; hasta entonces, ten paciencia y espera a que " +
+			"te respondamos.**\n\n" +
+			"Este proceso vinculará tu cuenta de PokéRogue con la cuenta de Discord con la que abriste este ticket, para que puedas iniciar sesión sin " +
+			"tu contraseña.\n" +
+			"__No tenemos forma__ de ver, comprobar, cambiar ni restablecer tu contraseña, pero una vez que hayas iniciado sesión podrás cambiarla tú mismo en el menú.\n" +
+			"Además, **NUNCA** compartas datos personales como contraseñas en ningún lugar ni con nadie, tampoco en estos hilos.",
+		TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION: "Imagen que muestra dónde está el panel de nombres de usuario",
+		TICKET_CREATED:                        "Se ha creado un nuevo ticket: <#%d>",
+		TICKET_ALREADY_EXISTS:                 "Ya tienes un ticket abierto, usa este: <#%d>",
+		COULD_NOT_CREATE_THREAD: "No he podido crear tu ticket de soporte. Por favor, inténtalo de nuevo.\n" +
+			"Si el problema continúa, pide ayuda en <#%d>.",
+		COULD_NOT_ADD_TO_THREAD: "He creado tu ticket, pero algo ha fallado al intentar darte acceso. " +
+			"Pide ayuda en <#%d> e indica que no pude darte acceso a tu ticket de contraseña.",
+		COULD_NOT_SEND_INSTRUCTIONS: "He creado tu ticket y te he añadido, " +
+			"pero algo ha fallado al enviar las instrucciones. Pide ayuda en <#%d> e indica que no pude enviar las instrucciones.",
+		COULD_NOT_GET_USER_ID:       "Algo ha fallado, no he podido obtener tu ID de usuario. Por favor, inténtalo de nuevo y, si el problema continúa, pide ayuda en <#%d>.",
+		GREETING:                    "¡Hola, %s!",
+		INACTIVITY_REMINDER:         "¡Hola, <@%d>! Hace tiempo que no sabemos de ti. Responde aquí si todavía necesitas ayuda.",
+		INACTIVITY_CLOSE_WARNING:    "De lo contrario, este ticket se cerrará automáticamente <t:%d:R>.",
+		TICKET_BANNED:               "No tienes permiso para abrir tickets.",
+		TICKET_BAN_ENDS:             "Podrás volver a abrir tickets <t:%d:R>.",
+		TICKET_COOLDOWN:             "Abriste un ticket hace poco. Podrás abrir otro <t:%d:R>.",
+		TICKET_RATE_LIMITED:         "Se están abriendo muchos tickets ahora mismo y nuestros ayudantes necesitan tiempo para atenderlos. Vuelve a intentarlo <t:%d:R>.",
+		INTAKE_TITLE:                "Abrir un ticket de contraseña",
+		INTAKE_USERNAME:             "Nombre de usuario",
+		INTAKE_USERNAME_HINT:        "El nombre de usuario de PokéRogue que crees haber usado",
+		INTAKE_ACCOUNT_CREATED:      "Fecha de creación de la cuenta",
+		INTAKE_ACCOUNT_CREATED_HINT: "Aproximadamente cuándo creaste tu cuenta, p. ej. \"primavera de 2024\"",
+		INTAKE_LAST_PLAYED:          "Última partida",
+		INTAKE_LAST_PLAYED_HINT:     "Aproximadamente cuándo jugaste por última vez, p. ej. \"la semana pasada\"",
+		INTAKE_PLATFORM:             "Plataforma y navegador",
+		INTAKE_PLATFORM_HINT:        "En qué juegas, p. ej. \"Android, Chrome\" o \"Windows, Firefox\"",
+	},
+	tempest.PORTUGUESE_BR_LANGUAGE: {
+		TICKET_INSTRUCTIONS: "### Olá, %s!\n" +
+			"Por favor, envie uma captura de tela da página de login __com o painel de nomes de usuário aberto ou o código de erro que aparecer__.\n" +
+			"Você precisa __clicar na engrenagem no canto superior esquerdo__ (veja a imagem anexada para encontrá-la)!\n" +
+			"**Lembre-se de que somos pessoas reais que dedicam seu tempo como voluntárias, então, por favor, não nos mencione várias vezes. " +
+			"Quando alguém estiver livre, vai entrar em contato para ajudar; até lá, tenha paciência e aguarde até que " +
+			"a gente te responda.**\n\n" +
+			"Este processo vai vincular sua conta do PokéRogue à conta do Discord que você usou para abrir este ticket, permitindo que você entre sem " +
+			"precisar da sua senha.\n" +
+			"__Não temos como__ acessar, verificar, alterar ou redefinir sua senha, mas depois de entrar você mesmo pode alterá-la no menu.\n" +
+			"Além disso, **NUNCA** compartilhe dados pessoais como senhas em nenhum lugar e com ninguém, inclusive nestes tópicos.",
+		TICKET_INSTRUCTIONS_IMAGE_DESCRIPTION: "Imagem mostrando onde fica o painel de nomes de usuário",
+		TICKET_CREATED:                        "Um novo ticket foi criado: <#%d>",
+		TICKET_ALREADY_EXISTS:                 "Você já tem um ticket aberto, use este: <#%d>",
+		COULD_NOT_CREATE_THREAD: "Não consegui criar seu ticket de suporte. Por favor, tente novamente.\n" +
+			"Se o problema continuar, peça ajuda em <#%d>.",
+		COULD_NOT_ADD_TO_THREAD: "Criei seu ticket, mas algo deu errado ao tentar te dar acesso a ele. " +
+			"Peça ajuda em <#%d> e avise que não consegui te dar acesso ao seu ticket de senha.",
+		COULD_NOT_SEND_INSTRUCTIONS: "Criei seu ticket e adicionei você a ele, " +
+			"mas algo deu errado ao enviar as instruções. Peça ajuda em <#%d> e avise que não consegui enviar as instruções.",
+		COULD_NOT_GET_USER_ID:       "Algo deu errado, não consegui obter seu ID de usuário. Por favor, tente novamente e, se o problema continuar, peça ajuda em <#%d>.",
+		GREETING:                    "Olá, %s!",
+		INACTIVITY_REMINDER:         "Olá, <@%d>! Faz um tempo que não temos notícias suas. Responda aqui se ainda precisar de ajuda.",
+		INACTIVITY_CLOSE_WARNING:    "Caso contrário, este ticket será fechado automaticamente <t:%d:R>.",
+		TICKET_BANNED:               "Você não tem permissão para abrir tickets.",
+		TICKET_BAN_ENDS:             "Você poderá abrir tickets novamente <t:%d:R>.",
+		TICKET_COOLDOWN:             "Você abriu um ticket recentemente. Você poderá abrir outro <t:%d:R>.",
+		TICKET_RATE_LIMITED:         "Muitos tickets estão sendo abertos agora, e nossos ajudantes precisam de tempo para atendê-los. Tente novamente <t:%d:R>.",
+		INTAKE_TITLE:                "Abrir um ticket de senha",
+		INTAKE_USERNAME:             "Nome de usuário",
+		INTAKE_USERNAME_HINT:        "O nome de usuário do PokéRogue que você acha que usou",
+		INTAKE_ACCOUNT_CREATED:      "Data de criação da conta",
+		INTAKE_ACCOUNT_CREATED_HINT: "Mais ou menos quando você criou sua conta, ex.: \"primavera de 2024\"",
+		INTAKE_LAST_PLAYED:          "Última vez que jogou",
+		INTAKE_LAST_PLAYED_HINT:     "Mais ou menos quando você jogou pela última vez, ex.: \"semana passada\"",
+		INTAKE_PLATFORM:             "Plataforma e navegador",
+		INTAKE_PLATFORM_HINT:        "Onde você joga, ex.: \"Android, Chrome\" ou \"Windows, Firefox\"",
+	},
+}
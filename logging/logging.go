@@ -0,0 +1,82 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Structured logging, with the interaction being handled attached to every line
+
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/amatsagu/tempest"
+)
+
+// Setup makes a logger writing JSON lines to stderr the default, for both log/slog and the log package
+func Setup(level slog.Level) {
+	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+}
+
+// The loggers of the interactions being handled, by interaction ID.
+// Handlers and helpers only have the interaction, so this is how they find its logger.
+var interactions sync.Map
+
+// Start registers the logger of an interaction until `done` is called. `kind` is the type of the interaction
+// and `name` its command or custom ID.
+func Start(itx *tempest.Interaction, kind string, name string) (done func()) {
+	logger := slog.Default().With(
+		slog.String("interaction_id", itx.ID.String()),
+		slog.String("interaction_type", kind),
+		slog.String("command", name),
+		slog.String("guild_id", itx.GuildID.String()),
+		slog.String("channel_id", itx.ChannelID.String()),
+		slog.String("user_id", userID(itx).String()),
+	)
+	interactions.Store(itx.ID, logger)
+
+	return func() {
+		interactions.Delete(itx.ID)
+	}
+}
+
+// For returns the logger of an interaction, or the default logger if it is not being handled
+func For(itx *tempest.Interaction) *slog.Logger {
+	if logger, ok := interactions.Load(itx.ID); ok {
+		return logger.(*slog.Logger)
+	}
+
+	return slog.Default()
+}
+
+// WithTicket adds the ticket the interaction is about to its logger, for the rest of its handling, and returns it
+func WithTicket(itx *tempest.Interaction, ticketNumber int64) *slog.Logger {
+	logger := For(itx).With(Ticket(ticketNumber))
+	if _, ok := interactions.Load(itx.ID); ok {
+		interactions.Store(itx.ID, logger)
+	}
+
+	return logger
+}
+
+// Ticket is the attribute identifying a ticket in log lines
+func Ticket(ticketNumber int64) slog.Attr {
+	return slog.Int64("ticket", ticketNumber)
+}
+
+// Return the ID of the user who triggered the interaction
+func userID(itx *tempest.Interaction) tempest.Snowflake {
+	if itx.Member != nil && itx.Member.User != nil {
+		return itx.Member.User.ID
+	}
+	if itx.User != nil {
+		return itx.User.ID
+	}
+
+	return 0
+}
@@ -10,75 +10,216 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/pagefaultgames/ticketune/commands"
+	"github.com/pagefaultgames/ticketune/config"
 	"github.com/pagefaultgames/ticketune/db"
+	githubClient "github.com/pagefaultgames/ticketune/github-client"
+	"github.com/pagefaultgames/ticketune/health"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/metrics"
 
 	"github.com/amatsagu/tempest"
 )
 
+// Configuration file read when TICKETUNE_CONFIG is not set, if it exists
+const defaultConfigFile = "ticketune.toml"
+
+// How long to wait for running interactions and background jobs when asked to stop.
+// Keep it below the grace period of the process manager (30 seconds by default for Docker and Kubernetes).
+const shutdownTimeout = 25 * time.Second
+
+// Return the path of the configuration file, or "" to configure the bot from environment variables only
+func configPath() string {
+	if path, ok := os.LookupEnv("TICKETUNE_CONFIG"); ok {
+		return path
+	}
+
+	if _, err := os.Stat(defaultConfigFile); err == nil {
+		return defaultConfigFile
+	}
+
+	return ""
+}
+
+// Log `err` and exit
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func main() {
+	// Log JSON from the start, so that configuration errors are reported like everything else
+	logging.Setup(slog.LevelInfo)
+
+	cfg, err := config.Load(configPath(), os.LookupEnv)
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+	logging.Setup(cfg.Logging.Level)
+
+	slog.Info("initializing the GitHub client")
+	gh, err := githubClient.New(cfg.GitHub)
+	if err != nil {
+		fatal("failed to initialize the GitHub client", err)
+	}
+
 	// open (or create) the database
-	log.Println("Initializing the database...")
-	err := db.Init()
+	slog.Info("initializing the database", "driver", cfg.Database.Driver)
+	store, err := db.Open(cfg.Database)
 	if err != nil {
-		log.Fatal("failed to initialize the database", err)
+		fatal("failed to initialize the database", err)
 	}
 
-	log.Println("Creating new Tempest client...")
+	bot := commands.New(cfg, store, gh)
+
+	slog.Info("creating the tempest client")
 	client := tempest.NewHTTPClient(tempest.HTTPClientOptions{
 		BaseClientOptions: tempest.BaseClientOptions{
 
-			Token: os.Getenv("DISCORD_BOT_TOKEN"),
+			Token: cfg.Discord.Token,
+			// Components whose custom IDs carry state, such as the queue's pagination buttons
+			ComponentHandler: bot.HandleDynamicComponent,
 		},
-		PublicKey: os.Getenv("DISCORD_PUBLIC_KEY"),
+		PublicKey: cfg.Discord.PublicKey,
 	})
 
-	addr := os.Getenv("LISTENING_ADDRESS")
-	guildID, err := tempest.StringToSnowflake(os.Getenv("DISCORD_GUILD_ID"))
-	if err != nil {
-		log.Fatal("failed to parse env variable to snowflake", err)
-	}
+	metrics.InstrumentDiscord(&client.BaseClient)
+
+	// Handlers are wrapped with bot.Command, bot.Component and bot.Modal so that shutting down waits for them,
+	// they are counted in the metrics, and a panic in one of them is reported instead of stopping the bot.
 
 	// Register a simple ping command
-	client.RegisterCommand(commands.PingCommand)
-	client.RegisterCommand(commands.CreateSupportTicketCommand)
-	client.RegisterComponent([]string{"open-ticket-button"}, commands.OpenTicketButtonCallback)
-	client.RegisterCommand(commands.GetUserTicketCommand)
-	client.RegisterCommand(commands.CloseCommand)
-	client.RegisterCommand(commands.TryDiscordCommand)
-	client.RegisterCommand(commands.FailDiscordCommand)
-	client.RegisterCommand(commands.NoSaveCommmand)
-	client.RegisterCommand(commands.RequestPanelCommand)
+	client.RegisterCommand(bot.Command(commands.PingCommand))
+	client.RegisterCommand(bot.Command(bot.CreateSupportTicketCommand()))
+	// The legacy button is on the panels sent before ticket categories existed, and opens password tickets
+	openTicketButtonIDs := []string{commands.LegacyOpenTicketButtonID}
+	for _, category := range cfg.TicketCategories() {
+		openTicketButtonIDs = append(openTicketButtonIDs, commands.OpenTicketButtonID(category.ID))
+		err = client.RegisterModal(commands.OpenTicketModalID(category.ID), bot.Modal(bot.HandleOpenTicketModal))
+		if err != nil {
+			fatal("failed to register intake form handler", err)
+		}
+	}
+	client.RegisterComponent(openTicketButtonIDs, bot.Component(bot.OpenTicketButtonCallback))
+	client.RegisterCommand(bot.Command(bot.GetUserTicketCommand()))
+	client.RegisterComponent([]string{commands.ClaimTicketButtonID}, bot.Component(bot.ClaimTicketButtonCallback))
+	client.RegisterCommand(bot.Command(bot.ClaimCommand()))
+	client.RegisterCommand(bot.Command(bot.UnclaimCommand()))
+	client.RegisterCommand(bot.Command(bot.AssignCommand()))
+	client.RegisterCommand(bot.Command(bot.QueueCommand()))
+	client.RegisterCommand(bot.Command(bot.CloseCommand()))
+	client.RegisterCommand(bot.Command(bot.TranscriptCommand()))
 	client.RegisterCommand(commands.OldAccountCommandGroup)
-	client.RegisterSubCommand(commands.OldAccountDefault, commands.OldAccountCommandGroup.Name)
-	client.RegisterSubCommand(commands.OldAccountSpecific, commands.OldAccountCommandGroup.Name)
-	client.RegisterCommand(commands.SayCommand)
-	client.RegisterCommand(commands.WhichAccountCommand)
-	client.RegisterCommand(commands.NewIssueCommand)
-	client.RegisterCommand(commands.UsernameScreenshotCommmand)
-	client.RegisterCommand(commands.SaveAccessCommmand)
-	err = client.RegisterModal(commands.CreateIssueModalId, commands.HandleNewIssueModal)
+	client.RegisterSubCommand(bot.Command(bot.OldAccountDefault()), commands.OldAccountCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.OldAccountSpecific()), commands.OldAccountCommandGroup.Name)
+	client.RegisterCommand(bot.Command(bot.SayCommand()))
+
+	// Register the canned responses helpers can send to ticket threads, and the commands to manage them
+	client.RegisterCommand(bot.Command(bot.ReplyCommand()))
+	client.RegisterCommand(commands.ResponseCommandGroup)
+	client.RegisterSubCommand(bot.Command(bot.ResponseCreate()), commands.ResponseCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.ResponseEdit()), commands.ResponseCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.ResponseDelete()), commands.ResponseCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.ResponseList()), commands.ResponseCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.ResponsePreview()), commands.ResponseCommandGroup.Name)
+	err = client.RegisterModal(commands.CannedResponseModalID, bot.Modal(bot.HandleCannedResponseModal))
 	if err != nil {
-		log.Fatal("failed to register new issue modal handler", err)
+		fatal("failed to register canned response modal handler", err)
 	}
-	client.RegisterCommand(commands.TechIssuesCommand)
-	client.RegisterCommand(commands.PingSpamCommand)
-	client.RegisterCommand(commands.HowResetPwCommand)
 
-	err = client.SyncCommandsWithDiscord([]tempest.Snowflake{guildID}, nil, false)
+	client.RegisterCommand(commands.TicketBanCommandGroup)
+	client.RegisterSubCommand(bot.Command(bot.TicketBanAdd()), commands.TicketBanCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.TicketBanRemove()), commands.TicketBanCommandGroup.Name)
+	client.RegisterSubCommand(bot.Command(bot.TicketBanList()), commands.TicketBanCommandGroup.Name)
+
+	client.RegisterCommand(bot.Command(commands.NewIssueCommand))
+	err = client.RegisterModal(commands.CreateIssueModalId, bot.Modal(bot.HandleNewIssueModal))
 	if err != nil {
-		log.Fatal("failed to sync local commands storage with Discord API", err)
+		fatal("failed to register new issue modal handler", err)
 	}
 
-	http.HandleFunc("POST /discord/callback", client.DiscordRequestHandler)
+	// Loaded after every other command is registered, so that a canned response cannot take the name of one
+	err = bot.LoadCannedResponses(&client.BaseClient, cfg.CannedResponses.File)
+	if err != nil {
+		fatal("failed to load canned responses", err)
+	}
 
-	log.Printf("Serving application at: %s/discord/callback\n", addr)
-	err = http.ListenAndServe(addr, nil)
+	err = bot.SyncCommands(&client.BaseClient, cfg.Discord.GuildID)
 	if err != nil {
-		log.Fatal("something went terribly wrong", err)
+		fatal("failed to sync local commands storage with Discord API", err)
+	}
+
+	// Cancelled when the bot is asked to stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Remind and eventually close tickets whose user stopped replying
+	bot.Go(&client.BaseClient, "inactivity", func() { bot.RunInactivityScheduler(ctx, &client.BaseClient) })
+
+	metrics.ObserveOpenTickets(func() (int, error) {
+		tickets, err := store.GetOpenTickets()
+		return len(tickets), err
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /discord/callback", client.DiscordRequestHandler)
+	mux.HandleFunc("GET /healthz", health.Live)
+	mux.HandleFunc("GET /readyz", health.Ready(
+		health.Check{Name: "database", Check: store.Ping},
+		health.Check{Name: "commands", Check: func(ctx context.Context) error { return bot.CommandsSynced() }},
+		health.Check{Name: "github", Check: func(ctx context.Context) error { return gh.CheckToken() }},
+	))
+	mux.Handle("GET /metrics", metrics.Handler())
+	server := &http.Server{Addr: cfg.Discord.ListeningAddress, Handler: mux}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	slog.Info("serving application", "address", cfg.Discord.ListeningAddress+"/discord/callback")
+	select {
+	case err = <-serveErr:
+		fatal("something went terribly wrong", err)
+	case <-ctx.Done():
 	}
+
+	// A second signal kills the bot right away
+	stop()
+	shutdown(server, bot, store)
+}
+
+// Stop receiving interactions, wait (for at most shutdownTimeout) for the ones being handled and for background jobs,
+// then close the store
+func shutdown(server *http.Server, bot *commands.Bot, store db.Store) {
+	slog.Info("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Returns once every interaction received got its initial response, but handlers may keep running after that
+	err := server.Shutdown(ctx)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		slog.Error("failed to stop the HTTP server", "error", err)
+	}
+
+	err = bot.Shutdown(ctx)
+	if err != nil {
+		slog.Warn("stopped without waiting for everything to finish", "error", err)
+	}
+
+	err = store.Close()
+	if err != nil {
+		slog.Error("failed to close the database", "error", err)
+	}
+
+	slog.Info("stopped")
 }
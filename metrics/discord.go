@@ -0,0 +1,66 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/amatsagu/tempest"
+)
+
+// InstrumentDiscord counts the failed requests the client makes to the Discord REST API in DiscordErrors.
+// Call it after anything else replacing the client's HTTP transport.
+func InstrumentDiscord(client *tempest.BaseClient) {
+	next := client.Rest.HTTPClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	client.Rest.HTTPClient.Transport = discordTransport{next: next}
+}
+
+type discordTransport struct {
+	next http.RoundTripper
+}
+
+func (t discordTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		DiscordErrors.WithLabelValues(r.Method+" "+discordRoute(r.URL.Path), "error").Inc()
+		return resp, err
+	}
+
+	if resp.StatusCode >= 400 {
+		DiscordErrors.WithLabelValues(r.Method+" "+discordRoute(r.URL.Path), strconv.Itoa(resp.StatusCode)).Inc()
+	}
+
+	return resp, nil
+}
+
+// Return the route of a Discord API path, with IDs and interaction tokens replaced by placeholders so that
+// there is one route per endpoint, e.g. "/channels/{id}/messages/{id}" for "/api/v10/channels/1/messages/2".
+func discordRoute(path string) string {
+	path = strings.TrimPrefix(path, "/api/v10")
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		// Interaction tokens follow the ID of the webhook or interaction they belong to
+		if i >= 2 && (segments[i-2] == "webhooks" || segments[i-2] == "interactions") {
+			segments[i] = "{token}"
+			continue
+		}
+
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,124 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Prometheus metrics of the bot, served at /metrics
+
+package metrics
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The kinds of interactions, used as the `type` label
+const (
+	INTERACTION_COMMAND      = "command"
+	INTERACTION_AUTOCOMPLETE = "autocomplete"
+	INTERACTION_COMPONENT    = "component"
+	INTERACTION_MODAL        = "modal"
+)
+
+// The `type` label of the panics in background jobs (see commands.Bot.Go)
+const JOB = "job"
+
+// The outcomes of creating a GitHub issue, used as the `outcome` label
+const (
+	ISSUE_CREATED      = "created"
+	ISSUE_RATE_LIMITED = "rate_limited"
+	ISSUE_TIMED_OUT    = "timed_out"
+	ISSUE_FAILED       = "failed"
+)
+
+// Why a user was not given a ticket, used as the `reason` label
+const (
+	REFUSED_BANNED       = "banned"
+	REFUSED_COOLDOWN     = "cooldown"
+	REFUSED_RATE_LIMITED = "rate_limited"
+)
+
+// What happened to an alert, used as the `outcome` label
+const (
+	ALERT_SENT         = "sent"
+	ALERT_DUPLICATE    = "duplicate"
+	ALERT_RATE_LIMITED = "rate_limited"
+	ALERT_FAILED       = "failed"
+)
+
+var (
+	// Interactions counts the interactions handled, by type and name (the command, or the custom ID of the component
+	// or modal). Interactions refused while shutting down are not counted.
+	Interactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_interactions_total",
+		Help: "Interactions handled, by type and command or custom ID.",
+	}, []string{"type", "name"})
+
+	// HandlerDuration observes how long interaction handlers ran, by type and name
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ticketune_interaction_handler_duration_seconds",
+		Help:    "How long interaction handlers ran, including their follow-up requests to Discord.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"type", "name"})
+
+	// DiscordErrors counts the requests to the Discord REST API that failed, by route and status code.
+	// Requests that got no response have the status "error".
+	DiscordErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_discord_rest_errors_total",
+		Help: "Failed Discord REST API requests, by route and status code.",
+	}, []string{"route", "status"})
+
+	// GitHubIssues counts the attempts to create a GitHub issue, by outcome (one of the ISSUE_ constants)
+	GitHubIssues = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_github_issues_total",
+		Help: "Attempts to create a GitHub issue, by outcome.",
+	}, []string{"outcome"})
+
+	// Panics counts the interaction handlers that panicked, by type and name, and the background jobs that panicked
+	Panics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_panics_total",
+		Help: "Interaction handlers and background jobs that panicked, by type and name.",
+	}, []string{"type", "name"})
+
+	// Alerts counts the alerts reported to the troubleshooting channel, by kind and outcome (one of the ALERT_ constants)
+	Alerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_alerts_total",
+		Help: "Alerts reported to the troubleshooting channel, by kind and outcome.",
+	}, []string{"kind", "outcome"})
+
+	// TicketsRefused counts the presses of the Open Ticket button that did not open a ticket, by reason (one of the
+	// REFUSED_ constants)
+	TicketsRefused = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ticketune_tickets_refused_total",
+		Help: "Tickets users were refused, by reason.",
+	}, []string{"reason"})
+)
+
+// ObserveOpenTickets reports the number of open tickets, counted by `count` whenever the metrics are scraped.
+// Must only be called once.
+func ObserveOpenTickets(count func() (int, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ticketune_open_tickets",
+		Help: "Tickets currently open.",
+	}, func() float64 {
+		n, err := count()
+		if err != nil {
+			slog.Error("failed to count open tickets for metrics", "error", err)
+			return math.NaN()
+		}
+
+		return float64(n)
+	})
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
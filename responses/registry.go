@@ -0,0 +1,48 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package responses
+
+import (
+	"slices"
+	"sync"
+)
+
+var (
+	activeMu sync.RWMutex
+	active   []CannedResponse // The canned responses currently available to helpers
+)
+
+// SetActive replaces the canned responses available to helpers
+func SetActive(responses []CannedResponse) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	active = slices.Clone(responses)
+}
+
+// Active returns the canned responses available to helpers
+func Active() []CannedResponse {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+
+	return slices.Clone(active)
+}
+
+// Find returns the active canned response with the given name
+func Find(name string) (CannedResponse, bool) {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+
+	for _, r := range active {
+		if r.Name == name {
+			return r, true
+		}
+	}
+
+	return CannedResponse{}, false
+}
@@ -0,0 +1,272 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Canned responses helpers can send to a ticket thread, loaded from a JSON file at startup
+
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/pagefaultgames/ticketune/i18n"
+
+	"github.com/amatsagu/tempest"
+)
+
+// CannedResponse is a message helpers can send to a ticket thread with a slash command of the same name
+type CannedResponse struct {
+	Name             string `json:"name"`                        // The name of the slash command
+	Description      string `json:"description"`                 // The description of the slash command
+	Body             string `json:"body"`                        // The message sent to the thread
+	Image            string `json:"image,omitempty"`             // URL of an image shown below the message
+	ImageDescription string `json:"image_description,omitempty"` // Alt text of the image
+	InvokerResponse  string `json:"invoker_response"`            // The ephemeral confirmation sent back to the helper
+	Ping             *bool  `json:"ping,omitempty"`              // Whether the user is pinged by default. Defaults to true
+
+	// Values the helper fills in when sending the response, used in the body as {name}
+	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// Translations of the message, by Discord locale. Body and ImageDescription above are in i18n.DEFAULT_LOCALE
+	Translations map[tempest.Language]Translation `json:"translations,omitempty"`
+}
+
+// Translation is the text of a canned response in another language
+type Translation struct {
+	Body             string `json:"body"`                        // The message sent to the thread
+	ImageDescription string `json:"image_description,omitempty"` // Alt text of the image. Defaults to the untranslated one
+}
+
+// Parameter is a value the helper fills in when sending a canned response, as an option of its slash command
+type Parameter struct {
+	Name        string `json:"name"`               // The name of the option, and of the variable in the body
+	Description string `json:"description"`        // The description of the option
+	Required    bool   `json:"required,omitempty"` // Whether the helper must fill in the parameter
+}
+
+// Localized returns the canned response as it should be sent to a user with the given locale, and the locale it is in.
+// If the response is not translated to `locale`, the closest available translation is used (see i18n.Fallbacks).
+func (r CannedResponse) Localized(locale tempest.Language) (CannedResponse, tempest.Language) {
+	for _, l := range i18n.Fallbacks(locale) {
+		t, ok := r.Translations[l]
+		if !ok {
+			continue
+		}
+
+		r.Body = t.Body
+		if t.ImageDescription != "" {
+			r.ImageDescription = t.ImageDescription
+		}
+
+		return r, l
+	}
+
+	return r, i18n.DEFAULT_LOCALE
+}
+
+// Locales returns the locales the canned response is available in, starting with i18n.DEFAULT_LOCALE
+func (r CannedResponse) Locales() []tempest.Language {
+	locales := []tempest.Language{i18n.DEFAULT_LOCALE}
+	for _, l := range slices.Sorted(maps.Keys(r.Translations)) {
+		if l != i18n.DEFAULT_LOCALE {
+			locales = append(locales, l)
+		}
+	}
+
+	return locales
+}
+
+// PingByDefault returns whether the user is pinged when the helper does not say otherwise
+func (r CannedResponse) PingByDefault() bool {
+	return r.Ping == nil || *r.Ping
+}
+
+// Discord's limits on slash commands and messages
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-structure
+const (
+	maxCommandCount           = 100
+	maxDescriptionLength      = 100
+	maxMessageLength          = 2000
+	maxImageDescriptionLength = 1024
+)
+
+// Longest greeting prepended to the body when pinging the user, e.g. "Hi <@snowflake>!\n", in any language
+var maxGreetingLength = func() int {
+	longest := 0
+	for locale := range i18n.LocaleNames {
+		greeting := i18n.Message(locale, i18n.GREETING, "<@18446744073709551615>") + "\n"
+		longest = max(longest, utf8.RuneCountInString(greeting))
+	}
+
+	return longest
+}()
+
+// Slash command names must be lowercase where possible, and at most 32 characters
+var commandNameRegex = regexp.MustCompile(`^[-_\p{Ll}\p{Lo}\p{N}]{1,32}$`)
+
+// Parameter names must also be usable as template variables
+var parameterNameRegex = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Slash commands have at most 25 options, and canned responses use one for pinging
+const maxParameterCount = 24
+
+// Option names canned response commands already use
+var reservedParameterNames = []string{"ping", "no-ping", "response", "language"}
+
+// Load reads and validates the canned responses in the JSON file at `path`.
+// Every validation problem is reported at once, joined into a single error.
+func Load(path string) ([]CannedResponse, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []CannedResponse
+	err = json.Unmarshal(raw, &responses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	err = Validate(responses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canned responses in %s: %w", path, err)
+	}
+
+	return responses, nil
+}
+
+// Validate checks that every canned response fits within Discord's limits, and that names are unique
+func Validate(responses []CannedResponse) error {
+	var errs []error
+
+	if len(responses) > maxCommandCount {
+		errs = append(errs, fmt.Errorf("%d responses exceeds Discord's limit of %d commands", len(responses), maxCommandCount))
+	}
+
+	seen := make(map[string]bool, len(responses))
+	for i, r := range responses {
+		if seen[r.Name] {
+			errs = append(errs, fmt.Errorf("response %d: duplicate name %q", i, r.Name))
+		}
+		seen[r.Name] = true
+
+		err := r.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("response %d (%q): %w", i, r.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the canned response fits within Discord's limits
+func (r CannedResponse) Validate() error {
+	var errs []error
+
+	if !commandNameRegex.MatchString(r.Name) {
+		errs = append(errs, errors.New("name must be 1-32 lowercase letters, numbers, dashes or underscores"))
+	}
+
+	descriptionLength := utf8.RuneCountInString(r.Description)
+	if descriptionLength == 0 || descriptionLength > maxDescriptionLength {
+		errs = append(errs, fmt.Errorf("description must be 1-%d characters, got %d", maxDescriptionLength, descriptionLength))
+	}
+
+	errs = append(errs, validateBody("body", r.Body))
+	errs = append(errs, r.validateVariables("body", r.Body))
+
+	invokerResponseLength := utf8.RuneCountInString(r.InvokerResponse)
+	if invokerResponseLength == 0 || invokerResponseLength > maxMessageLength {
+		errs = append(errs, fmt.Errorf("invoker_response must be 1-%d characters, got %d", maxMessageLength, invokerResponseLength))
+	}
+
+	if r.Image != "" {
+		u, err := url.Parse(r.Image)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("image must be an http(s) URL, got %q", r.Image))
+		}
+	}
+
+	if utf8.RuneCountInString(r.ImageDescription) > maxImageDescriptionLength {
+		errs = append(errs, fmt.Errorf("image_description must be at most %d characters", maxImageDescriptionLength))
+	}
+
+	errs = append(errs, r.validateParameters())
+
+	for _, locale := range slices.Sorted(maps.Keys(r.Translations)) {
+		t := r.Translations[locale]
+		if !i18n.IsLocale(locale) {
+			errs = append(errs, fmt.Errorf("translations: %q is not a Discord locale", locale))
+		}
+
+		errs = append(errs, validateBody(fmt.Sprintf("translations.%s.body", locale), t.Body))
+		errs = append(errs, r.validateVariables(fmt.Sprintf("translations.%s.body", locale), t.Body))
+		if utf8.RuneCountInString(t.ImageDescription) > maxImageDescriptionLength {
+			errs = append(errs, fmt.Errorf("translations.%s.image_description must be at most %d characters", locale, maxImageDescriptionLength))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check that the parameters can be slash command options, and do not shadow built in variables
+func (r CannedResponse) validateParameters() error {
+	var errs []error
+
+	if len(r.Parameters) > maxParameterCount {
+		errs = append(errs, fmt.Errorf("at most %d parameters are allowed, got %d", maxParameterCount, len(r.Parameters)))
+	}
+
+	seen := make(map[string]bool, len(r.Parameters))
+	for i, p := range r.Parameters {
+		switch {
+		case !parameterNameRegex.MatchString(p.Name):
+			errs = append(errs, fmt.Errorf("parameters[%d]: name must be 1-32 lowercase letters, numbers or underscores", i))
+		case slices.Contains(BuiltinVars, p.Name) || slices.Contains(reservedParameterNames, p.Name):
+			errs = append(errs, fmt.Errorf("parameters[%d]: name %q is reserved", i, p.Name))
+		case seen[p.Name]:
+			errs = append(errs, fmt.Errorf("parameters[%d]: duplicate name %q", i, p.Name))
+		}
+		seen[p.Name] = true
+
+		descriptionLength := utf8.RuneCountInString(p.Description)
+		if descriptionLength == 0 || descriptionLength > maxDescriptionLength {
+			errs = append(errs, fmt.Errorf("parameters[%d]: description must be 1-%d characters, got %d", i, maxDescriptionLength, descriptionLength))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check that every variable in a body is either built in or a parameter of the response
+func (r CannedResponse) validateVariables(field string, body string) error {
+	var errs []error
+	for _, name := range Variables(body) {
+		if !slices.Contains(BuiltinVars, name) && !slices.ContainsFunc(r.Parameters, func(p Parameter) bool { return p.Name == name }) {
+			errs = append(errs, fmt.Errorf("%s uses unknown variable {%s}", field, name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check that a message body leaves room for the greeting within Discord's message length limit
+func validateBody(field string, body string) error {
+	bodyLength := utf8.RuneCountInString(body)
+	if bodyLength == 0 || bodyLength > maxMessageLength-maxGreetingLength {
+		return fmt.Errorf("%s must be 1-%d characters, got %d", field, maxMessageLength-maxGreetingLength, bodyLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package responses
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Scores of the ways a query can match a response; higher is a better match
+const (
+	scoreNamePrefix      = 100
+	scoreNameContains    = 80
+	scoreNameSubsequence = 60
+	scoreDescription     = 40
+	scoreBody            = 20
+)
+
+// Return whether the characters of `query` appear in `s` in order, not necessarily next to each other.
+// e.g. "rpt" matches "request-panel-topleft"
+func isSubsequence(query, s string) bool {
+	queryRunes := []rune(query)
+	i := 0
+	for _, r := range s {
+		if i == len(queryRunes) {
+			break
+		}
+		if r == queryRunes[i] {
+			i++
+		}
+	}
+
+	return i == len(queryRunes)
+}
+
+// Return how well `query` (lowercase) matches the response, or 0 if it does not match at all
+func score(r CannedResponse, query string) int {
+	name := strings.ToLower(r.Name)
+
+	switch {
+	case strings.HasPrefix(name, query):
+		return scoreNamePrefix
+	case strings.Contains(name, query):
+		return scoreNameContains
+	case isSubsequence(query, name):
+		return scoreNameSubsequence
+	}
+
+	// Every word of the query must appear in the description or body
+	words := strings.Fields(query)
+	if matchesAllWords(strings.ToLower(r.Description), words) {
+		return scoreDescription
+	}
+	if matchesAllWords(strings.ToLower(r.Body), words) {
+		return scoreBody
+	}
+	for _, t := range r.Translations {
+		if matchesAllWords(strings.ToLower(t.Body), words) {
+			return scoreBody
+		}
+	}
+
+	return 0
+}
+
+// Return whether every word appears in `s`
+func matchesAllWords(s string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(s, word) {
+			return false
+		}
+	}
+
+	return len(words) > 0
+}
+
+// Search returns up to `limit` active canned responses matching `query` by name or text, best matches first.
+// An empty query returns the first `limit` responses, sorted by name.
+func Search(query string, limit int) []CannedResponse {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	type match struct {
+		response CannedResponse
+		score    int
+	}
+
+	var matches []match
+	for _, r := range Active() {
+		s := scoreNamePrefix
+		if query != "" {
+			s = score(r, query)
+		}
+		if s > 0 {
+			matches = append(matches, match{r, s})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.response.Name, b.response.Name))
+	})
+
+	results := make([]CannedResponse, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		results = append(results, m.response)
+	}
+
+	return results
+}
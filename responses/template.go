@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package responses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Variables every canned response can use, filled in from the ticket and the helper sending the response
+const (
+	VAR_USER          = "user"          // Mention of the ticket's user
+	VAR_HELPER        = "helper"        // Display name of the helper sending the response
+	VAR_TICKET_NUMBER = "ticket_number" // The ticket's number
+	VAR_TICKET_AGE    = "ticket_age"    // When the ticket was opened, as a relative timestamp, e.g. "3 days ago"
+)
+
+// The names of the variables every canned response can use
+var BuiltinVars = []string{VAR_USER, VAR_HELPER, VAR_TICKET_NUMBER, VAR_TICKET_AGE}
+
+// A variable in a template, e.g. {ticket_number}
+var templateVarRegex = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+// Value is the value of a template variable, which decides how it is escaped
+type Value interface {
+	render() string
+}
+
+// Text is a value shown as plain text, with Discord markdown and mentions escaped.
+// Use it for anything a user or helper typed.
+type Text string
+
+// Code is a value shown as inline code, which Discord does not parse markdown or mentions in
+type Code string
+
+// Raw is a value inserted as is, for markdown the bot builds itself such as mentions and timestamps
+type Raw string
+
+// Characters with a meaning in Discord markdown or mentions wherever they are
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `_`, `\_`, "~", `\~`, "`", "\\`", `|`, `\|`,
+	`>`, `\>`, `<`, `\<`, `#`, `\#`, `[`, `\[`, `]`, `\]`,
+	`(`, `\(`, `)`, `\)`, `@`, `\@`,
+	"\r", " ", "\n", " ",
+)
+
+// The rest of an emoji shortcode after its first colon, e.g. "smile:" in ":smile:"
+var shortcodeRegex = regexp.MustCompile(`^[\w\\+-]+:`)
+
+// EscapeMarkdown escapes `s` so Discord shows it as plain text on a single line, without formatting or mentions
+func EscapeMarkdown(s string) string {
+	escaped := markdownEscaper.Replace(s)
+
+	var b strings.Builder
+	for i := range len(escaped) {
+		switch {
+		// A list or subtext starts with a dash, and the value may start a line
+		case i == 0 && escaped[i] == '-':
+			b.WriteByte('\\')
+		// A colon starts a link such as https://, or an emoji shortcode such as :smile:
+		case escaped[i] == ':' && (strings.HasPrefix(escaped[i+1:], "//") || shortcodeRegex.MatchString(escaped[i+1:])):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(escaped[i])
+	}
+
+	return b.String()
+}
+
+func (t Text) render() string {
+	return EscapeMarkdown(string(t))
+}
+
+func (c Code) render() string {
+	if c == "" {
+		return ""
+	}
+
+	// A backtick would end the code span, so swap it for a lookalike
+	code := strings.NewReplacer("`", "ˋ", "\r", " ", "\n", " ").Replace(string(c))
+	return "``" + code + "``"
+}
+
+func (r Raw) render() string {
+	return string(r)
+}
+
+// Vars are the values of the variables of a template, by name
+type Vars map[string]Value
+
+// Render replaces every {name} in `template` with the value of the variable `name`.
+// Variables without a value are left untouched.
+func Render(template string, vars Vars) string {
+	return templateVarRegex.ReplaceAllStringFunc(template, func(match string) string {
+		value, ok := vars[match[1:len(match)-1]]
+		if !ok {
+			return match
+		}
+
+		return value.render()
+	})
+}
+
+// Variables returns the names of the variables used in `template`, in order of appearance
+func Variables(template string) []string {
+	var names []string
+	for _, match := range templateVarRegex.FindAllStringSubmatch(template, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
@@ -0,0 +1,58 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package responses
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+		want  string
+	}{
+		{"text everyone ping", Text("@everyone"), `Hi \@everyone!`},
+		{"text user mention", Text("<@123>"), `Hi \<\@123\>!`},
+		{"text channel mention", Text("<#123>"), `Hi \<\#123\>!`},
+		{"text bold", Text("**bold**"), `Hi \*\*bold\*\*!`},
+		{"text backtick", Text("a`b"), "Hi a\\`b!"},
+		{"text newlines", Text("a\r\nb\n# c"), `Hi a  b \# c!`},
+		{"text dash inside", Text("foo-bar"), `Hi foo-bar!`},
+		{"text leading dash", Text("- item"), `Hi \- item!`},
+		{"text colon", Text("time: 12:30"), `Hi time: 12:30!`},
+		{"text emoji shortcode", Text(":smile:"), `Hi \:smile:!`},
+		{"text link", Text("https://example.com"), `Hi https\://example.com!`},
+		{"code everyone ping", Code("@everyone"), "Hi ``@everyone``!"},
+		{"code user mention", Code("<@123>"), "Hi ``<@123>``!"},
+		{"code channel mention", Code("<#123>"), "Hi ``<#123>``!"},
+		{"code bold", Code("**bold**"), "Hi ``**bold**``!"},
+		{"code backtick", Code("a`b"), "Hi ``aˋb``!"},
+		{"code newlines", Code("a\r\nb"), "Hi ``a  b``!"},
+		{"code empty", Code(""), "Hi !"},
+		{"raw everyone ping", Raw("@everyone"), "Hi @everyone!"},
+		{"raw user mention", Raw("<@123>"), "Hi <@123>!"},
+		{"raw channel mention", Raw("<#123>"), "Hi <#123>!"},
+		{"raw bold", Raw("**bold**"), "Hi **bold**!"},
+		{"raw backtick", Raw("a`b"), "Hi a`b!"},
+		{"raw newlines", Raw("a\nb"), "Hi a\nb!"},
+	}
+
+	for _, test := range tests {
+		got := Render("Hi {name}!", Vars{"name": test.value})
+		if got != test.want {
+			t.Errorf("%s: rendered %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRenderLeavesUnknownVariables(t *testing.T) {
+	got := Render("{user} asked about {topic} in {Channel}", Vars{"user": Raw("<@123>")})
+	want := "<@123> asked about {topic} in {Channel}"
+	if got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+}
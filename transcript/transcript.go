@@ -0,0 +1,218 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+// Renders the messages of a ticket thread into Markdown and self-contained HTML transcripts
+
+package transcript
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/amatsagu/tempest"
+)
+
+const timeFormat = "2006-01-02 15:04:05 UTC"
+
+// Info describes the ticket a transcript was generated for
+type Info struct {
+	TicketNumber int64             // The ticket's number in the database
+	ThreadName   string            // The name of the ticket thread
+	ThreadID     tempest.Snowflake // The ID of the ticket thread
+	UserID       tempest.Snowflake // The user who opened the ticket
+	ClosedBy     tempest.Snowflake // The helper who closed the ticket
+	OpenedAt     time.Time         // When the ticket was opened
+	ClosedAt     time.Time         // When the ticket was closed
+}
+
+// A message, flattened into the parts shown in a transcript
+type entry struct {
+	Author      string
+	AuthorID    tempest.Snowflake
+	Bot         bool
+	Timestamp   string
+	Edited      bool
+	Content     string
+	Attachments []tempest.Attachment
+}
+
+// Return "Display Name (username)", or just the username if the user has no display name
+func authorName(user *tempest.User) string {
+	if user == nil {
+		return "Unknown user"
+	}
+
+	if user.GlobalName == "" || user.GlobalName == user.Username {
+		return user.Username
+	}
+
+	return user.GlobalName + " (" + user.Username + ")"
+}
+
+// Collect the text of a message: its content, the text displays of any components, and any embeds.
+// Components V2 messages (such as the ticket instructions) have no content of their own.
+func messageText(msg tempest.Message) string {
+	parts := []string{}
+	if msg.Content != "" {
+		parts = append(parts, msg.Content)
+	}
+
+	for _, component := range msg.Components {
+		parts = appendComponentText(parts, component)
+	}
+
+	for _, embed := range msg.Embeds {
+		if embed.Title != "" {
+			parts = append(parts, embed.Title)
+		}
+		if embed.Description != "" {
+			parts = append(parts, embed.Description)
+		}
+		for _, field := range embed.Fields {
+			parts = append(parts, field.Name+": "+field.Value)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// Recursively append the text displays found in a component
+func appendComponentText(parts []string, component tempest.AnyComponent) []string {
+	switch c := component.(type) {
+	case tempest.TextDisplayComponent:
+		parts = append(parts, c.Content)
+	case tempest.SectionComponent:
+		for _, text := range c.Components {
+			parts = append(parts, text.Content)
+		}
+	case tempest.ContainerComponent:
+		for _, child := range c.Components {
+			parts = appendComponentText(parts, child)
+		}
+	case tempest.MediaGalleryComponent:
+		for _, item := range c.Items {
+			parts = append(parts, item.Media.URL)
+		}
+	}
+
+	return parts
+}
+
+// Convert messages (oldest first) into transcript entries
+func entries(messages []tempest.Message) []entry {
+	res := make([]entry, 0, len(messages))
+	for _, msg := range messages {
+		e := entry{
+			Author:      authorName(msg.Author),
+			Edited:      msg.EditedTimestamp != nil,
+			Content:     messageText(msg),
+			Attachments: msg.Attachments,
+		}
+		if msg.Author != nil {
+			e.AuthorID = msg.Author.ID
+			e.Bot = msg.Author.Bot
+		}
+		if msg.Timestamp != nil {
+			e.Timestamp = msg.Timestamp.UTC().Format(timeFormat)
+		}
+
+		res = append(res, e)
+	}
+
+	return res
+}
+
+// Markdown renders the transcript as a Markdown document
+func Markdown(info Info, messages []tempest.Message) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Ticket #%d: %s\n\n", info.TicketNumber, info.ThreadName)
+	fmt.Fprintf(&sb, "- Thread ID: %d\n", info.ThreadID)
+	fmt.Fprintf(&sb, "- Opened by: %d, at %s\n", info.UserID, info.OpenedAt.UTC().Format(timeFormat))
+	fmt.Fprintf(&sb, "- Closed by: %d, at %s\n", info.ClosedBy, info.ClosedAt.UTC().Format(timeFormat))
+	fmt.Fprintf(&sb, "- Messages: %d\n", len(messages))
+
+	for _, e := range entries(messages) {
+		fmt.Fprintf(&sb, "\n---\n\n**%s** (%d) — %s", e.Author, e.AuthorID, e.Timestamp)
+		if e.Edited {
+			sb.WriteString(" (edited)")
+		}
+		sb.WriteString("\n\n")
+
+		if e.Content != "" {
+			sb.WriteString(e.Content)
+			sb.WriteString("\n")
+		}
+
+		for _, a := range e.Attachments {
+			fmt.Fprintf(&sb, "\n- Attachment: [%s](%s)", a.FileName, a.URL)
+		}
+		if len(e.Attachments) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Ticket #{{.Info.TicketNumber}}: {{.Info.ThreadName}}</title>
+<style>
+body { font-family: sans-serif; background: #313338; color: #dbdee1; margin: 2em auto; max-width: 60em; }
+header { border-bottom: 1px solid #4e5058; margin-bottom: 1em; }
+.message { padding: 0.5em 0; border-bottom: 1px solid #3f4147; }
+.author { font-weight: bold; color: #f2f3f5; }
+.bot { font-size: 0.75em; background: #5865f2; color: #fff; border-radius: 3px; padding: 0 0.3em; margin-left: 0.3em; }
+.meta { font-size: 0.8em; color: #949ba4; margin-left: 0.5em; }
+.content { white-space: pre-wrap; margin-top: 0.3em; }
+a { color: #00a8fc; }
+</style>
+</head>
+<body>
+<header>
+<h1>Ticket #{{.Info.TicketNumber}}: {{.Info.ThreadName}}</h1>
+<ul>
+<li>Thread ID: {{.Info.ThreadID}}</li>
+<li>Opened by: {{.Info.UserID}}, at {{.OpenedAt}}</li>
+<li>Closed by: {{.Info.ClosedBy}}, at {{.ClosedAt}}</li>
+<li>Messages: {{len .Entries}}</li>
+</ul>
+</header>
+{{range .Entries}}<div class="message">
+<span class="author" title="{{.AuthorID}}">{{.Author}}</span>{{if .Bot}}<span class="bot">BOT</span>{{end}}<span class="meta">{{.Timestamp}}{{if .Edited}} (edited){{end}}</span>
+{{if .Content}}<div class="content">{{.Content}}</div>{{end}}
+{{range .Attachments}}<div class="attachment">Attachment: <a href="{{.URL}}">{{.FileName}}</a></div>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+// HTML renders the transcript as a self-contained HTML document
+func HTML(info Info, messages []tempest.Message) (string, error) {
+	var sb strings.Builder
+	err := htmlTemplate.Execute(&sb, struct {
+		Info     Info
+		OpenedAt string
+		ClosedAt string
+		Entries  []entry
+	}{
+		Info:     info,
+		OpenedAt: info.OpenedAt.UTC().Format(timeFormat),
+		ClosedAt: info.ClosedAt.UTC().Format(timeFormat),
+		Entries:  entries(messages),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -37,6 +37,12 @@ type CreateThreadWithoutMessageParams struct {
 	Invitable           bool            `json:"invitable"`                       // Whether non-moderators can add other non-moderators to a thread; only available when creating a private thread, and defaults to true if omitted
 }
 
+// https://discord.com/developers/docs/resources/channel#modify-channel-json-params-thread
+// Parameters currently unused by Ticketune are omitted
+type ModifyThreadParams struct {
+	Name string `json:"name,omitempty"` // 1-100 character channel name
+}
+
 // Channel represents a Discord channel or thread object (partial, for threads).
 // https://discord.com/developers/docs/resources/channel#channel-object-channel-structure
 type Channel struct {
@@ -48,6 +54,7 @@ type Channel struct {
 	ThreadMetadata             *ThreadMetadata     `json:"thread_metadata,omitempty"`               // thread-specific fields not needed by other channels
 	DefaultAutoArchiveDuration int                 `json:"default_auto_archive_duration,omitempty"` // default duration, copied onto newly created threads, in minutes, threads will stop showing in the channel list after the specified period of inactivity, can be set to: 60, 1440, 4320, 10080
 	Flags                      int                 `json:"flags,omitempty"`                         // https://discord.com/developers/docs/resources/channel#channel-object-channel-flags
+	LastMessageID              *tempest.Snowflake  `json:"last_message_id,omitempty"`               // the id of the last message sent in this channel (or thread); may be null, and may not point to an existing message
 	// MemberCount                int                 `json:"member_count,omitempty"`
 	// TotalMessageSent           int                 `json:"total_message_sent,omitempty"`
 	// LastPinTimestamp           string              `json:"last_pin_timestamp,omitempty"`
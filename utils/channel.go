@@ -12,8 +12,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 
-	"github.com/pagefaultgames/ticketune/constants"
 	"github.com/pagefaultgames/ticketune/types"
 
 	"github.com/amatsagu/tempest"
@@ -35,11 +35,62 @@ func GetChannelFromID(client *tempest.BaseClient, cid tempest.Snowflake) (types.
 	return channel, nil
 }
 
-func CheckIfPasswordTicketChannel(channel types.Channel) bool {
-	// Check if this is a thread in the ticket channel
-	if channel.ParentID != constants.TICKET_CHANNEL_ID || channel.Type != tempest.GUILD_PRIVATE_THREAD_CHANNEL_TYPE {
+// Maximum number of messages Discord returns per request to the get channel messages endpoint
+const messagesPageSize = 100
+
+// Fetch every message in a channel, oldest first, paging backwards from the most recent message.
+// https://discord.com/developers/docs/resources/message#get-channel-messages
+func GetChannelMessages(client *tempest.BaseClient, cid tempest.Snowflake) ([]tempest.Message, error) {
+	var messages []tempest.Message
+	route := fmt.Sprintf("/channels/%d/messages?limit=%d", cid, messagesPageSize)
+
+	for {
+		response, err := client.Rest.Request(http.MethodGet, route, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []tempest.Message
+		err = json.Unmarshal(response, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, page...)
+		if len(page) < messagesPageSize {
+			break
+		}
+
+		// Pages are ordered newest first, so the last message is the oldest one we have
+		route = fmt.Sprintf("/channels/%d/messages?limit=%d&before=%d", cid, messagesPageSize, page[len(page)-1].ID)
+	}
+
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// Fetch a single message from a channel
+func GetChannelMessage(client *tempest.BaseClient, cid tempest.Snowflake, mid tempest.Snowflake) (tempest.Message, error) {
+	response, err := client.Rest.Request(http.MethodGet, fmt.Sprintf("/channels/%d/messages/%d", cid, mid), nil)
+	if err != nil {
+		return tempest.Message{}, err
+	}
+
+	var message tempest.Message
+	err = json.Unmarshal(response, &message)
+	if err != nil {
+		return tempest.Message{}, err
+	}
+
+	return message, nil
+}
+
+// Return whether the channel is a ticket thread, i.e. a private thread in one of the ticket channels
+func CheckIfTicketChannel(channel types.Channel, ticketChannelIDs []tempest.Snowflake) bool {
+	if channel.Type != tempest.GUILD_PRIVATE_THREAD_CHANNEL_TYPE {
 		return false
 	}
 
-	return true
+	// Check if this is a thread in a ticket channel
+	return slices.Contains(ticketChannelIDs, channel.ParentID)
 }
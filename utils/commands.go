@@ -9,45 +9,100 @@ package utils
 
 import (
 	"database/sql"
-	"log"
 
 	"github.com/amatsagu/tempest"
 	"github.com/pagefaultgames/ticketune/constants"
+	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/i18n"
+	"github.com/pagefaultgames/ticketune/logging"
+	"github.com/pagefaultgames/ticketune/types"
 )
 
+// Optional behavior of SayCommandTemplate
+type SayOptions struct {
+	ImageURL         string           // URL of an image to show below the message, if not empty
+	ImageDescription string           // Alt text of the image
+	NoPingByDefault  bool             // If true, the user is only pinged when the `ping` option is set, instead of unless `no-ping` is set
+	Locale           tempest.Language // The locale of the message, used to greet the user when pinging them. Defaults to English
+}
+
+// Return whether the user should be pinged, according to the command's `no-ping` or `ping` option.
+// Commands may offer either or both options; if neither is set, the default from `opts` is used.
+func shouldPing(itx *tempest.CommandInteraction, opts SayOptions) bool {
+	// Discard errors; if the option is missing, we use the default
+	if ping, err := GetOption[bool](itx, "ping", false); err == nil {
+		return ping
+	}
+
+	if noPing, err := GetOption[bool](itx, "no-ping", false); err == nil {
+		return !noPing
+	}
+
+	return !opts.NoPingByDefault
+}
+
 // Base say command functionality reusable by multiple command implementations
 // Parameters:
 // `itx“: The command interaction to respond to
+// `store`, `ticketChannelIDs`: Where the ticket of the thread is looked up, see GetUserFromThread
 // `content“: The message content to send to the thread
 // `invokerResponse`: The message to send back to the command invoker on success. On error, a relevant error message will be sent instead.
+// `opts`: Optional behavior, such as an image to attach
 func SayCommandTemplate(itx *tempest.CommandInteraction,
+	store db.TicketStore,
+	ticketChannelIDs []tempest.Snowflake,
 	content string,
 	invokerResponse string,
+	opts SayOptions,
 ) {
 	// Get the user associated with this thread (this handles responding to the interaction on error)
-	userID, err := GetUserFromThread(itx)
+	userID, err := GetUserFromThread(itx, store, ticketChannelIDs)
 	if err != sql.ErrNoRows && err != nil {
 		return
 	}
 
-	// Discard error; if the option is missing, we default to `false`
-	noPing, _ := GetOption[bool](itx, "no-ping", false)
+	ping := shouldPing(itx, opts)
+
+	// Only the ticket's user may be pinged by the message
+	message := types.CreateMessageParams{AllowedMentions: &tempest.AllowedMentions{}}
 
 	// The message to send publicly to the thread
-	if err == nil && !noPing {
-		content = "Hi <@" + userID.String() + ">!\n" + content
+	if err == nil && ping {
+		content = i18n.Message(opts.Locale, i18n.GREETING, "<@"+userID.String()+">") + "\n" + content
+		message.AllowedMentions.Users = []tempest.Snowflake{userID}
 	}
 
 	if err != nil {
-		log.Println("Error fetching user for thread:", err)
+		logging.For(itx.Interaction).Warn("failed to fetch user for thread", "error", err)
 		invokerResponse = constants.COULD_NOT_FIND_USER_TO_PING
 	}
 
+	if opts.ImageURL == "" {
+		message.Content = content
+	} else {
+		message.Flags = tempest.IS_COMPONENTS_V2_MESSAGE_FLAG
+		message.Components = []tempest.LayoutComponent{
+			tempest.ContainerComponent{
+				Type: tempest.CONTAINER_COMPONENT_TYPE,
+				Components: []tempest.AnyComponent{
+					tempest.TextDisplayComponent{
+						Type:    tempest.TEXT_DISPLAY_COMPONENT_TYPE,
+						Content: content,
+					},
+					tempest.MediaGalleryComponent{
+						Type: tempest.MEDIA_GALLERY_COMPONENT_TYPE,
+						Items: []tempest.MediaGalleryItem{{
+							Media:       tempest.UnfurledMediaItem{URL: opts.ImageURL},
+							Description: opts.ImageDescription,
+						}},
+					},
+				},
+			},
+		}
+	}
+
 	// Send the user a message
-	_, err = itx.Client.SendLinearMessage(
-		itx.ChannelID,
-		content,
-	)
+	_, err = SendDiscordMessage(itx.Client, itx.ChannelID, message, nil, true)
 	if err != nil {
 		itx.SendLinearReply("Something went wrong trying to send the message: "+err.Error(), true)
 		return
@@ -10,36 +10,52 @@ package utils
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/pagefaultgames/ticketune/db"
+	"github.com/pagefaultgames/ticketune/logging"
 
 	"github.com/amatsagu/tempest"
 )
 
-var ErrNotATicketThread = errors.New("this command can only be used in a password ticket thread")
+var ErrNotATicketThread = errors.New("this command can only be used in a ticket thread")
 var ErrCantFetchChannel = errors.New("could not fetch channel information")
 
 // Get the channel and user ID associated with a command interaction
-// Errors if the
-func GetUserFromThread(itx *tempest.CommandInteraction) (tempest.Snowflake, error) {
-	// If this is not a thread in the ticket channel, do nothing
+// Errors if the channel is not a thread in one of the ticket channels, or if `store` has no ticket for it
+func GetUserFromThread(itx *tempest.CommandInteraction, store db.TicketStore, ticketChannelIDs []tempest.Snowflake) (tempest.Snowflake, error) {
+	// If this is not a thread in a ticket channel, do nothing
 	channel, err := GetChannelFromID(itx.Client, itx.ChannelID)
 	if err != nil {
 		itx.SendLinearReply("Error fetching channel information, likely because I'm be missing permissions for this channel.", true)
 		return tempest.Snowflake(0), err
 	}
 
-	if !CheckIfPasswordTicketChannel(channel) {
-		itx.SendLinearReply("This command can only be used in a password ticket thread", true)
+	if !CheckIfTicketChannel(channel, ticketChannelIDs) {
+		itx.SendLinearReply("This command can only be used in a ticket thread", true)
 		return tempest.Snowflake(0), ErrNotATicketThread
 	}
 
-	userID, err := db.Get().GetThreadUser(itx.ChannelID)
+	ticket, err := store.GetThreadTicket(itx.ChannelID)
 	if err != nil {
 		return tempest.Snowflake(0), err
 	}
+	// The caller is about to act on this ticket, so tag the rest of its log lines with it
+	logging.WithTicket(itx.Interaction, ticket.Number)
 
-	return userID, nil
+	return ticket.UserID, nil
+}
+
+// Edit the message a component is attached to, after the interaction has been acknowledged with a deferred update.
+// https://discord.com/developers/docs/interactions/receiving-and-responding#edit-original-interaction-response
+func EditComponentMessage(itx *tempest.ComponentInteraction, content tempest.ResponseMessageData) error {
+	_, err := itx.Client.Rest.Request(
+		http.MethodPatch,
+		"/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/@original",
+		content,
+	)
+
+	return err
 }
 
 var ErrMissingOption = errors.New("option is missing")
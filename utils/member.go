@@ -0,0 +1,29 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pagefault Games
+ * SPDX-FileContributor: SirzBenjie
+ *
+ * SPDX-License-Identifier: AGPL-3.0-or-later
+ */
+
+package utils
+
+import (
+	"github.com/amatsagu/tempest"
+)
+
+// Return the name the member is shown with in the guild: their nickname, display name or username, in that order
+func DisplayName(member *tempest.Member) string {
+	if member == nil || member.User == nil {
+		return ""
+	}
+
+	if member.Nickname != "" {
+		return member.Nickname
+	}
+
+	if member.User.GlobalName != "" {
+		return member.User.GlobalName
+	}
+
+	return member.User.Username
+}
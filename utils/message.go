@@ -22,7 +22,7 @@ var ErrMissingRequiredField = errors.New("at least one of content, embeds, compo
 
 // A replacement for `tempest.SendMessage` that accepts `types.CreateMessageParams` instead of `tempest.Message`
 // Necessary, as tempest does not include support for fields like AllowedMentions
-// At the moment, does not support files.
+// Files are sent as multipart attachments.
 // Also adds an additional parameter, `discardResponse`, for when the message response is not needed
 func SendDiscordMessage(
 	client *tempest.BaseClient,